@@ -3,7 +3,6 @@ module github.com/xeus2001/interview-accountapi
 go 1.18
 
 require (
-	github.com/google/uuid v1.3.0 // indirect
-	golang.org/x/sys v0.0.0-20220114195835-da31bd327af9 // indirect
-	golang.org/x/tools v0.1.9 // indirect
+	github.com/google/uuid v1.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
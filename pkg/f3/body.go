@@ -0,0 +1,41 @@
+package f3
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// MaxResponseBody is the maximal amount of bytes read from the body of a response. Successful responses with a larger
+// body are rejected with ErrResponse.
+var MaxResponseBody int64 = 16 << 20
+
+// maxDrainedBody is the maximal amount of bytes drained from the unread rest of a response body before closing it.
+// If more bytes remain, the connection is closed instead of being returned to the pool.
+const maxDrainedBody = 64 << 10
+
+// errBodyTooLarge is returned by readBody, when the body exceeds MaxResponseBody.
+var errBodyTooLarge = errors.New("response body exceeds the maximal size")
+
+// readBody reads up to MaxResponseBody bytes of the body of the given response and closes the body.
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp.Body == nil {
+		return nil, nil
+	}
+	defer discard(resp)
+	body, e := ioutil.ReadAll(io.LimitReader(resp.Body, MaxResponseBody+1))
+	if e == nil && int64(len(body)) > MaxResponseBody {
+		return body[:MaxResponseBody], errBodyTooLarge
+	}
+	return body, e
+}
+
+// discard drains the rest of the body of the response up to maxDrainedBody bytes and closes it, so that the
+// connection can be reused.
+func discard(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.CopyN(ioutil.Discard, resp.Body, maxDrainedBody)
+		_ = resp.Body.Close()
+	}
+}
@@ -0,0 +1,98 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newConnectionCountingServer returns a test server that answers every kind of response the client handles and
+// counts the connections opened by clients.
+func newConnectionCountingServer(connections *int32) *httptest.Server {
+	account := createTestAccount(true)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/health":
+			_, _ = w.Write([]byte(`{"status":"up"}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error_message":"account already exists"}`))
+		case r.URL.Path == "/organisation/accounts":
+			_ = json.NewEncoder(w).Encode(&f3.AccountsEnvelope{Data: []*f3.Account{account}})
+		case strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/broken"):
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(strings.Repeat("x", 2*f3.MaxErrorBody)))
+		case strings.HasSuffix(r.URL.Path, "/malformed"):
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+		}
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(connections, 1)
+		}
+	}
+	server.Start()
+	return server
+}
+
+func TestClient_NoConnectionLeaks(t *testing.T) {
+	var connections int32
+	server := newConnectionCountingServer(&connections)
+	defer server.Close()
+	// A single in-flight slot makes every call block forever, if a previous call did not close its body.
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(f3.NoRetry),
+		f3.WithLimits(f3.Limits{MaxInFlight: 1, FailFast: true}))
+	existing := createTestAccount(true)
+
+	calls := []func() bool{
+		func() bool { return client.IsHealthy() },
+		func() bool { _, e := client.FetchAccount(f3.IntegrationTestAccountId); return e == nil },
+		func() bool { _, e := client.FetchAccount("missing"); return e.ErrorCode() == f3.ErrNotFound },
+		func() bool { _, e := client.FetchAccount("broken"); return e.ErrorCode() == f3.ErrServer },
+		func() bool { _, e := client.FetchAccount("malformed"); return e != nil },
+		func() bool { return client.DeleteAccount(f3.IntegrationTestAccountId, 0) == nil },
+		func() bool { _, e := client.CreateAccount(existing); return e.ErrorCode() == f3.ErrConflict },
+		func() bool {
+			envelope, e := client.ListAccounts(nil, nil)
+			return e == nil && envelope != nil
+		},
+	}
+	for i := 0; i < 3000; i++ {
+		if !calls[i%len(calls)]() {
+			t.Fatalf("Call %d returned an unexpected result", i)
+		}
+	}
+	if opened := atomic.LoadInt32(&connections); opened > 2 {
+		t.Errorf("Expected the connection to be reused, but %d connections were opened", opened)
+	}
+}
+
+func TestClient_CanceledRequestsCloseBodies(t *testing.T) {
+	var connections int32
+	server := newConnectionCountingServer(&connections)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLimits(f3.Limits{MaxInFlight: 1, FailFast: true}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 100; i++ {
+		if _, e := client.FetchAccountWithContext(ctx, f3.IntegrationTestAccountId); e == nil || e.ErrorCode() != f3.ErrCanceled {
+			t.Fatalf("Expected the request to be canceled, but got: %v", e)
+		}
+	}
+	if _, e := client.FetchAccount(f3.IntegrationTestAccountId); e != nil {
+		t.Fatalf("Expected the in-flight slot to be released, but got: %s", e.Error())
+	}
+}
@@ -0,0 +1,200 @@
+package f3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errCircuitOpen is returned by send, when the circuit breaker rejects a request.
+var errCircuitOpen = errors.New("the circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed is the normal state, in which all requests are sent.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen is the state after too many requests failed, in which all requests fail fast with ErrCircuitOpen.
+	CircuitOpen
+
+	// CircuitHalfOpen is the state after the circuit was open for the configured time, in which the next request
+	// probes the health of the service to decide whether to close the circuit again.
+	CircuitHalfOpen
+)
+
+// String returns the human-readable name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerConfig configures the circuit breaker of a client. Failures are requests that could not be sent, timed
+// out, including requests of which the deadline of the context exceeded, or were answered with 429 (Too Many Requests)
+// or any 5xx status code. Requests canceled by the caller are not counted at all.
+type CircuitBreakerConfig struct {
+	// Window is the duration of the window in which the failure rate is measured, a new window starts empty.
+	Window time.Duration
+
+	// MinRequests is the minimal amount of requests in a window, before the failure rate is evaluated.
+	MinRequests int
+
+	// FailureRate is the rate of failures, between 0 and 1, at which the circuit opens.
+	FailureRate float64
+
+	// OpenTimeout is the time the circuit stays open, before it becomes half-open and the health of the service is
+	// probed using the health check.
+	OpenTimeout time.Duration
+
+	// OnStateChange is an optional callback invoked whenever the state of the circuit changes. It is called
+	// synchronously by the goroutine that caused the change and should therefore return quickly.
+	OnStateChange func(from CircuitState, to CircuitState)
+}
+
+// DefaultCircuitBreakerConfig is a reasonable default configuration for the circuit breaker.
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	Window:      10 * time.Second,
+	MinRequests: 10,
+	FailureRate: 0.5,
+	OpenTimeout: 5 * time.Second,
+}
+
+// WithCircuitBreaker enables the circuit breaker with the given configuration. By default, the client does not use a
+// circuit breaker.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(c *Client) {
+		c.breaker = &circuitBreaker{config: config, state: CircuitClosed, windowStart: time.Now()}
+	}
+}
+
+// CircuitState returns the current state of the circuit breaker. If the client does not use a circuit breaker, the
+// state is always CircuitClosed.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	c.breaker.mutex.Lock()
+	defer c.breaker.unlock()
+	return c.breaker.state
+}
+
+// circuitBreaker is the implementation of the circuit breaker.
+type circuitBreaker struct {
+	config      CircuitBreakerConfig
+	mutex       sync.Mutex
+	state       CircuitState
+	openedAt    time.Time
+	windowStart time.Time
+	requests    int
+	failures    int
+	probing     bool
+	changes     []CircuitState
+}
+
+// allow tests if a request for the given operation may be sent. The health check is used as probe of the circuit
+// breaker and is therefore always allowed. When the circuit is half-open, the first caller probes the health of the
+// service, while all others are rejected.
+func (c *Client) allow(ctx context.Context, op operation) bool {
+	b := c.breaker
+	if b == nil || op.name == opHealth.name {
+		return true
+	}
+	b.mutex.Lock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.config.OpenTimeout {
+		b.transition(CircuitHalfOpen)
+	}
+	if b.state == CircuitClosed {
+		b.unlock()
+		return true
+	}
+	if b.state == CircuitOpen || b.probing {
+		b.unlock()
+		return false
+	}
+	b.probing = true
+	b.unlock()
+
+	healthy := c.IsHealthyWithContext(ctx)
+
+	b.mutex.Lock()
+	defer b.unlock()
+	b.probing = false
+	if healthy {
+		b.transition(CircuitClosed)
+	} else if ctx.Err() == nil {
+		b.transition(CircuitOpen)
+	}
+	return healthy
+}
+
+// record records the outcome of a request for the given operation. A request canceled by the caller is not recorded,
+// a request of which the deadline exceeded is a failure.
+func (c *Client) record(ctx context.Context, op operation, resp *http.Response, e error) {
+	b := c.breaker
+	if b == nil || op.name == opHealth.name || ctx.Err() == context.Canceled {
+		return
+	}
+	failed := e != nil || ctx.Err() != nil || isRetryableStatus(resp.StatusCode)
+	b.mutex.Lock()
+	defer b.unlock()
+	if b.state != CircuitClosed {
+		return
+	}
+	if time.Since(b.windowStart) >= b.config.Window {
+		b.windowStart = time.Now()
+		b.requests = 0
+		b.failures = 0
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+	if b.requests >= b.config.MinRequests && float64(b.failures) >= b.config.FailureRate*float64(b.requests) {
+		b.transition(CircuitOpen)
+	}
+}
+
+// transition changes the state of the circuit breaker; requires the mutex to be held. The callback is notified about
+// the change after the mutex is released.
+func (b *circuitBreaker) transition(to CircuitState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	switch to {
+	case CircuitOpen:
+		b.openedAt = time.Now()
+	case CircuitClosed:
+		b.windowStart = time.Now()
+		b.requests = 0
+		b.failures = 0
+	}
+	if len(b.changes) == 0 {
+		b.changes = append(b.changes, from)
+	}
+	b.changes = append(b.changes, to)
+}
+
+// unlock releases the mutex and then notifies the callback about all state changes made while holding it.
+func (b *circuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mutex.Unlock()
+	if b.config.OnStateChange != nil {
+		for i := 1; i < len(changes); i++ {
+			b.config.OnStateChange(changes[i-1], changes[i])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var (
+		healthy  int32
+		requests int32
+		mutex    sync.Mutex
+		changes  []string
+	)
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isHealthy := atomic.LoadInt32(&healthy) == 1
+		if strings.HasSuffix(r.URL.Path, "/health") {
+			if isHealthy {
+				_, _ = w.Write([]byte(`{"status":"up"}`))
+			} else {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		atomic.AddInt32(&requests, 1)
+		if !isHealthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+
+	config := f3.CircuitBreakerConfig{
+		Window:      time.Minute,
+		MinRequests: 2,
+		FailureRate: 0.5,
+		OpenTimeout: 50 * time.Millisecond,
+		OnStateChange: func(from f3.CircuitState, to f3.CircuitState) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	}
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(f3.NoRetry), f3.WithCircuitBreaker(config))
+
+	for i := 0; i < 2; i++ {
+		if _, e := client.FetchAccount(account.Id); e == nil || e.ErrorCode() != f3.ErrServer {
+			t.Fatalf("Expected a server error, got: %v", e)
+		}
+	}
+	if client.CircuitState() != f3.CircuitOpen {
+		t.Fatalf("Expected the circuit to be open, but is %s", client.CircuitState())
+	}
+	if _, e := client.FetchAccount(account.Id); e == nil || e.ErrorCode() != f3.ErrCircuitOpen {
+		t.Fatalf("Expected the request to be rejected, got: %v", e)
+	}
+	if requests != 2 {
+		t.Errorf("The open circuit must not send requests, but %d were received", requests)
+	}
+
+	// While the service is unhealthy, the probe fails and the circuit opens again.
+	time.Sleep(60 * time.Millisecond)
+	if _, e := client.FetchAccount(account.Id); e == nil || e.ErrorCode() != f3.ErrCircuitOpen {
+		t.Fatalf("Expected the request to be rejected after the failed probe, got: %v", e)
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Fatalf("Expected the circuit to be closed after a successful probe, got: %v", e)
+	}
+	if client.CircuitState() != f3.CircuitClosed {
+		t.Errorf("Expected the circuit to be closed, but is %s", client.CircuitState())
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	expected := "closed->open,open->half-open,half-open->open,open->half-open,half-open->closed"
+	if strings.Join(changes, ",") != expected {
+		t.Errorf("Expected the state changes %s, but got %s", expected, strings.Join(changes, ","))
+	}
+}
+
+func TestClient_CircuitBreaker_Deadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := f3.CircuitBreakerConfig{Window: time.Minute, MinRequests: 2, FailureRate: 0.5, OpenTimeout: time.Minute}
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(f3.NoRetry), f3.WithCircuitBreaker(config))
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2; i++ {
+		if _, e := client.FetchAccountWithContext(canceled, f3.IntegrationTestAccountId); e == nil {
+			t.Fatalf("Expected the canceled request to fail")
+		}
+	}
+	if client.CircuitState() != f3.CircuitClosed {
+		t.Fatalf("Canceled requests must not open the circuit, but it is %s", client.CircuitState())
+	}
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, e := client.FetchAccountWithContext(ctx, f3.IntegrationTestAccountId)
+		cancel()
+		if e == nil || e.ErrorCode() != f3.ErrTimeout {
+			t.Fatalf("Expected a timeout, got: %v", e)
+		}
+	}
+	if client.CircuitState() != f3.CircuitOpen {
+		t.Errorf("Expected exceeded deadlines to open the circuit, but it is %s", client.CircuitState())
+	}
+}
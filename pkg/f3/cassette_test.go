@@ -0,0 +1,78 @@
+package f3_test
+
+import (
+	"flag"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"net/http"
+	"os"
+	"testing"
+)
+
+var record = flag.String("f3.record", "", "Record the cassettes against the given endpoint instead of replaying them")
+
+// cassetteClient returns a client replaying the cassette with the given name or, when the flag f3.record is given,
+// a client recording the cassette against the account API. Cassettes must be recorded against the real account API,
+// the test fails, if the cassette is missing and recording was not requested.
+func cassetteClient(t *testing.T, name string) *f3.Client {
+	path := "testdata/cassettes/" + name + ".json"
+	var transport http.RoundTripper
+	endpoint := "http://replay/v1"
+	if len(*record) > 0 {
+		recorder := f3test.NewRecorder(nil)
+		t.Cleanup(func() {
+			if e := recorder.Cassette().Save(path); e != nil {
+				t.Errorf("Failed to save the cassette: %s", e)
+			}
+		})
+		transport, endpoint = recorder, *record
+	} else {
+		if _, e := os.Stat(path); os.IsNotExist(e) {
+			t.Fatalf("The cassette %s is missing, run make test-record with the docker stack", path)
+		}
+		transport = f3test.NewReplayer(t, path)
+	}
+	return f3.NewClient(f3.WithEndPoint(endpoint), f3.WithTransport(transport), f3.WithRetryPolicy(f3.NoRetry))
+}
+
+func TestCassette_AccountLifecycle(t *testing.T) {
+	client := cassetteClient(t, "account-lifecycle")
+
+	if !client.IsHealthy() {
+		t.Fatalf("Expected the account API to be healthy")
+	}
+	account := createTestAccount(false)
+	created, e := client.CreateAccount(account)
+	if e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+	if created.Id != account.Id || created.Version == nil || *created.Version != 0 || created.CreatedOn == nil {
+		t.Errorf("Unexpected created account: %+v", created)
+	}
+	if _, e = client.CreateAccount(account); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for the duplicate account, got: %v", e)
+	}
+	fetched, e := client.FetchAccount(account.Id)
+	if e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	if fetched.Attr == nil || fetched.Attr.AccountNumber != account.Attr.AccountNumber {
+		t.Errorf("Unexpected fetched account: %+v", fetched)
+	}
+	list, e := client.ListAccounts(&f3.AccountFilter{AccountNumber: []string{account.Attr.AccountNumber}}, &f3.Page{Size: 10})
+	if e != nil {
+		t.Fatalf("Failed to list the accounts: %s", e.Error())
+	}
+	if len(list.Data) != 1 || list.Data[0].Id != account.Id {
+		t.Errorf("Expected the list to contain only the created account, got: %+v", list.Data)
+	}
+	if e = client.DeleteAccount(account.Id, 1); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for the wrong version, got: %v", e)
+	}
+	if e = client.DeleteAccount(account.Id, 0); e != nil {
+		t.Fatalf("Failed to delete the account: %s", e.Error())
+	}
+	if _, e = client.FetchAccount(account.Id); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected the deleted account not to be found, got: %v", e)
+	}
+}
@@ -0,0 +1,195 @@
+package f3
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// AccountAmendmentFilter filters the account amendments to list. Every slice holds the values to accept, an
+// amendment must match at least one value of every non-empty field.
+type AccountAmendmentFilter struct {
+	OrganisationId []string
+	AccountId      []string
+
+	// SubmissionStatus accepts only amendments with a submission in the given status.
+	SubmissionStatus SubmissionStatusString
+
+	// SubmittedFrom and SubmittedTo accept only amendments submitted in the given period.
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+}
+
+// encode adds the filter to the given query.
+func (f *AccountAmendmentFilter) encode(query url.Values) {
+	if f == nil {
+		return
+	}
+	for name, values := range map[string][]string{
+		"organisation_id": f.OrganisationId,
+		"account_id":      f.AccountId,
+	} {
+		if len(values) > 0 {
+			query.Set(fmt.Sprintf("filter[%s]", name), strings.Join(values, ","))
+		}
+	}
+	encodeSubmissionFilter(query, f.SubmissionStatus, f.SubmittedFrom, f.SubmittedTo)
+}
+
+// ListAccountAmendments returns a single page of the account amendments matching the given filter. If no filter is
+// given, all amendments are listed, if no page is given, the first page with the default size of the account API is
+// returned.
+func (c *Client) ListAccountAmendments(filter *AccountAmendmentFilter, page *Page) (*AccountAmendmentsEnvelope, Err) {
+	return c.ListAccountAmendmentsWithContext(context.Background(), filter, page)
+}
+
+// ListAccountAmendmentsWithContext is like ListAccountAmendments, but the request is bound to the given context. If the
+// context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) ListAccountAmendmentsWithContext(ctx context.Context, filter *AccountAmendmentFilter, page *Page) (*AccountAmendmentsEnvelope, Err) {
+	return c.listAccountAmendments(ctx, c.listAmendmentsUri(filter, page))
+}
+
+// listAmendmentsUri returns the uri of the given page of the account amendments matching the filter.
+func (c *Client) listAmendmentsUri(filter *AccountAmendmentFilter, page *Page) string {
+	uri := c.amendmentUri
+	query := url.Values{}
+	filter.encode(query)
+	page.encode(query)
+	if len(query) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, query.Encode())
+	}
+	return uri
+}
+
+// listAccountAmendments lists the account amendments from the given uri.
+func (c *Client) listAccountAmendments(ctx context.Context, uri string) (*AccountAmendmentsEnvelope, Err) {
+	ctx, end := c.begin(ctx, opListAccountAmendments)
+	var envelope AccountAmendmentsEnvelope
+	er := call(ctx, c, opListAccountAmendments, http.MethodGet, uri, (*any)(nil), &envelope)
+	end(er)
+	if er != nil {
+		return nil, er
+	}
+	return &envelope, nil
+}
+
+// AccountAmendmentIterator iterates over all account amendments of a list.
+type AccountAmendmentIterator struct {
+	Iterator[*AccountAmendment]
+}
+
+// IterateAccountAmendments returns an iterator over all account amendments matching the given filter, fetching pages
+// of the given size.
+func (c *Client) IterateAccountAmendments(ctx context.Context, filter *AccountAmendmentFilter, pageSize int) *AccountAmendmentIterator {
+	uri := c.listAmendmentsUri(filter, &Page{Size: pageSize})
+	return &AccountAmendmentIterator{newIterator(ctx, c, uri, func(ctx context.Context, uri string) ([]*AccountAmendment, *Links, Err) {
+		envelope, er := c.listAccountAmendments(ctx, uri)
+		if er != nil {
+			return nil, nil, er
+		}
+		return envelope.Data, envelope.Links, nil
+	})}
+}
+
+// Amendment returns the current account amendment.
+func (it *AccountAmendmentIterator) Amendment() *AccountAmendment {
+	return it.Value()
+}
+
+// CreateAccountAmendment creates the given account amendment and returns it as returned from the server. If the
+// amendment has no organisation identifier, the one of the client is used. The account is not changed before the
+// amendment is submitted, see CreateAccountAmendmentSubmission.
+func (c *Client) CreateAccountAmendment(amendment *AccountAmendment) (*AccountAmendment, Err) {
+	return c.CreateAccountAmendmentWithContext(context.Background(), amendment)
+}
+
+// CreateAccountAmendmentWithContext is like CreateAccountAmendment, but the request is bound to the given context. If
+// the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) CreateAccountAmendmentWithContext(ctx context.Context, amendment *AccountAmendment) (*AccountAmendment, Err) {
+	ctx, end := c.begin(ctx, opCreateAccountAmendment)
+	var er Err
+	var envelope AccountAmendmentEnvelope
+	if amendment == nil {
+		er = err{code: ErrRequest, msg: "No account amendment given"}
+	} else {
+		if len(amendment.OrganisationId) == 0 && len(c.organisationId) > 0 {
+			withOrganisation := *amendment
+			withOrganisation.OrganisationId = c.organisationId
+			amendment = &withOrganisation
+		}
+		er = call(ctx, c, opCreateAccountAmendment, http.MethodPost, c.amendmentUri, &AccountAmendmentEnvelope{amendment}, &envelope)
+	}
+	created, er := present(envelope.Data, er)
+	end(er)
+	return created, er
+}
+
+// FetchAccountAmendment returns the account amendment with the given id or ErrNotFound, if it does not exist.
+func (c *Client) FetchAccountAmendment(amendmentId string) (*AccountAmendment, Err) {
+	return c.FetchAccountAmendmentWithContext(context.Background(), amendmentId)
+}
+
+// FetchAccountAmendmentWithContext is like FetchAccountAmendment, but the request is bound to the given context. If the
+// context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) FetchAccountAmendmentWithContext(ctx context.Context, amendmentId string) (*AccountAmendment, Err) {
+	ctx, end := c.begin(ctx, opFetchAccountAmendment, "account_amendment_id", amendmentId)
+	var envelope AccountAmendmentEnvelope
+	uri := fmt.Sprintf("%s/%s", c.amendmentUri, url.QueryEscape(amendmentId))
+	er := call(ctx, c, opFetchAccountAmendment, http.MethodGet, uri, (*any)(nil), &envelope)
+	fetched, er := present(envelope.Data, er)
+	end(er)
+	return fetched, er
+}
+
+// amendmentSubmissionsUri returns the uri of the submissions of the given account amendment.
+func (c *Client) amendmentSubmissionsUri(amendmentId string) string {
+	return fmt.Sprintf("%s/%s/submissions", c.amendmentUri, url.QueryEscape(amendmentId))
+}
+
+// CreateAccountAmendmentSubmission submits the account amendment with the given id and returns the new submission.
+// The account is changed asynchronously, use FetchAccountAmendmentSubmission to follow the status of the submission.
+func (c *Client) CreateAccountAmendmentSubmission(amendmentId string) (*AccountAmendmentSubmission, Err) {
+	return c.CreateAccountAmendmentSubmissionWithContext(context.Background(), amendmentId)
+}
+
+// CreateAccountAmendmentSubmissionWithContext is like CreateAccountAmendmentSubmission, but the request is bound to the
+// given context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled
+// respectively ErrTimeout is returned.
+func (c *Client) CreateAccountAmendmentSubmissionWithContext(ctx context.Context, amendmentId string) (*AccountAmendmentSubmission, Err) {
+	ctx, end := c.begin(ctx, opCreateAccountAmendmentSubmission, "account_amendment_id", amendmentId)
+	submission := AccountAmendmentSubmission{}
+	submission.Id = uuid.New().String()
+	submission.Type = TypeAccountAmendmentSubmission
+	submission.OrganisationId = c.organisationId
+	var envelope AccountAmendmentSubmissionEnvelope
+	er := call(ctx, c, opCreateAccountAmendmentSubmission, http.MethodPost, c.amendmentSubmissionsUri(amendmentId), &AccountAmendmentSubmissionEnvelope{&submission}, &envelope)
+	created, er := present(envelope.Data, er)
+	end(er)
+	return created, er
+}
+
+// FetchAccountAmendmentSubmission returns the submission with the given id of the account amendment with the given
+// id or ErrNotFound, if it does not exist.
+func (c *Client) FetchAccountAmendmentSubmission(amendmentId string, submissionId string) (*AccountAmendmentSubmission, Err) {
+	return c.FetchAccountAmendmentSubmissionWithContext(context.Background(), amendmentId, submissionId)
+}
+
+// FetchAccountAmendmentSubmissionWithContext is like FetchAccountAmendmentSubmission, but the request is bound to the
+// given context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled
+// respectively ErrTimeout is returned.
+func (c *Client) FetchAccountAmendmentSubmissionWithContext(ctx context.Context, amendmentId string, submissionId string) (*AccountAmendmentSubmission, Err) {
+	ctx, end := c.begin(ctx, opFetchAccountAmendmentSubmission, "account_amendment_id", amendmentId, "submission_id", submissionId)
+	var envelope AccountAmendmentSubmissionEnvelope
+	uri := fmt.Sprintf("%s/%s", c.amendmentSubmissionsUri(amendmentId), url.QueryEscape(submissionId))
+	er := call(ctx, c, opFetchAccountAmendmentSubmission, http.MethodGet, uri, (*any)(nil), &envelope)
+	fetched, er := present(envelope.Data, er)
+	end(er)
+	return fetched, er
+}
@@ -0,0 +1,117 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newAmendmentServer returns a test server that stores account amendments in memory and confirms every submission.
+func newAmendmentServer(t *testing.T) *httptest.Server {
+	var mutex sync.Mutex
+	amendments := map[string]*f3.AccountAmendment{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/organisation/accountamendments"), "/")[1:]
+		switch {
+		case len(path) == 0 && r.Method == http.MethodPost:
+			var envelope f3.AccountAmendmentEnvelope
+			_ = json.NewDecoder(r.Body).Decode(&envelope)
+			amendments[envelope.Data.Id] = envelope.Data
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case len(path) == 0:
+			accountId := r.URL.Query().Get("filter[account_id]")
+			envelope := f3.AccountAmendmentsEnvelope{Data: []*f3.AccountAmendment{}}
+			for _, amendment := range amendments {
+				if amendment.Relationships.Account.Data[0].Id == accountId {
+					envelope.Data = append(envelope.Data, amendment)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case amendments[path[0]] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case len(path) == 1:
+			_ = json.NewEncoder(w).Encode(&f3.AccountAmendmentEnvelope{Data: amendments[path[0]]})
+		case len(path) == 2 && r.Method == http.MethodPost:
+			var envelope f3.AccountAmendmentSubmissionEnvelope
+			_ = json.NewDecoder(r.Body).Decode(&envelope)
+			if envelope.Data.Type != f3.TypeAccountAmendmentSubmission {
+				t.Errorf("Submitted an invalid submission: %v", envelope.Data)
+			}
+			envelope.Data.Attr = &f3.AccountAmendmentSubmissionAttr{Status: f3.SubmissionDeliveryConfirmed}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case len(path) == 3:
+			submission := &f3.AccountAmendmentSubmission{Attr: &f3.AccountAmendmentSubmissionAttr{Status: f3.SubmissionDeliveryConfirmed}}
+			submission.Id = path[2]
+			_ = json.NewEncoder(w).Encode(&f3.AccountAmendmentSubmissionEnvelope{Data: submission})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestNewAccountAmendment(t *testing.T) {
+	account := createTestAccount(true)
+	version := uint64(3)
+	account.Version = &version
+	amendment := f3.NewAccountAmendment(account, "Marriage").WithName("Mrs", "Jane", "Smith")
+	if amendment.Type != f3.TypeAccountAmendment || amendment.OrganisationId != account.OrganisationId {
+		t.Errorf("Created an invalid amendment: %v", amendment)
+	}
+	reference := amendment.Relationships.Account.Data[0]
+	if reference.Id != account.Id || reference.Type != f3.TypeAccount || reference.Version != 3 {
+		t.Errorf("Expected a reference to version 3 of the account, but got: %v", reference)
+	}
+	raw, _ := json.Marshal(f3.NewAmendmentAccountReference(createTestAccount(true)))
+	if !strings.Contains(string(raw), `"version":0`) {
+		t.Errorf("Expected the version 0 to be sent, but got: %s", raw)
+	}
+}
+
+func TestClient_AccountAmendments(t *testing.T) {
+	server := newAmendmentServer(t)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+	account := createTestAccount(true)
+
+	created, e := client.CreateAccountAmendment(f3.NewAccountAmendment(account, "Marriage").WithName("Mrs", "Jane", "Smith"))
+	if e != nil {
+		t.Fatalf("Failed to create the amendment: %s", e.Error())
+	}
+	fetched, e := client.FetchAccountAmendment(created.Id)
+	if e != nil || fetched.Attr.ModifyReason != "Marriage" || len(fetched.Attr.Name) != 3 {
+		t.Fatalf("Failed to fetch the amendment: %v", e)
+	}
+	if _, e = client.FetchAccountAmendment("unknown"); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected not found for an unknown amendment, but got: %v", e)
+	}
+
+	count := 0
+	it := client.IterateAccountAmendments(context.Background(), &f3.AccountAmendmentFilter{AccountId: []string{account.Id}}, 10)
+	for it.Next() {
+		if it.Amendment().Id != created.Id {
+			t.Errorf("Listed the wrong amendment: %v", it.Amendment())
+		}
+		count++
+	}
+	if it.Err() != nil || count != 1 {
+		t.Errorf("Expected one amendment, but got %d: %v", count, it.Err())
+	}
+
+	submission, e := client.CreateAccountAmendmentSubmission(created.Id)
+	if e != nil {
+		t.Fatalf("Failed to submit the amendment: %s", e.Error())
+	}
+	fetchedSubmission, e := client.FetchAccountAmendmentSubmission(created.Id, submission.Id)
+	if e != nil || fetchedSubmission.Attr.Status != f3.SubmissionDeliveryConfirmed {
+		t.Fatalf("Failed to fetch the submission: %v", e)
+	}
+}
@@ -0,0 +1,54 @@
+package f3
+
+import "context"
+
+// DeleteMode selects how DeleteAccountLatest treats accounts that do not exist.
+type DeleteMode int
+
+const (
+	// DeleteExisting deletes the account and returns ErrNotFound, if the account does not exist.
+	DeleteExisting DeleteMode = iota
+
+	// EnsureAbsent deletes the account and treats an account that does not exist, for example because it was
+	// already deleted, as success.
+	EnsureAbsent
+)
+
+// DeleteAccountLatest deletes the account with the given id without knowing its version. The latest version is
+// fetched first, if the account was modified concurrently, so the delete is rejected with a conflict, the version is
+// fetched again, until the configured amount of attempts is reached (see WithUpdateAttempts). In that case ErrConflict
+// is returned with a VersionConflict as cause.
+func (c *Client) DeleteAccountLatest(accountId string, mode DeleteMode) Err {
+	return c.DeleteAccountLatestWithContext(context.Background(), accountId, mode)
+}
+
+// DeleteAccountLatestWithContext is like DeleteAccountLatest, but all requests are bound to the given context. If the
+// context is canceled or its deadline exceeded before the delete finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) DeleteAccountLatestWithContext(ctx context.Context, accountId string, mode DeleteMode) Err {
+	var lastConflict Err
+	for attempt := 1; attempt <= c.updateAttempts || attempt == 1; attempt++ {
+		account, er := c.FetchAccountWithContext(ctx, accountId)
+		if er != nil {
+			if er.ErrorCode() == ErrNotFound && mode == EnsureAbsent {
+				return nil
+			}
+			return er
+		}
+		if account.Version == nil {
+			return err{code: ErrResponse, msg: "The fetched account has no version"}
+		}
+		version := *account.Version
+		er = c.DeleteAccountWithContext(ctx, accountId, version)
+		if er == nil || (er.ErrorCode() == ErrNotFound && mode == EnsureAbsent) {
+			return nil
+		}
+		if er.ErrorCode() != ErrConflict {
+			return er
+		}
+		c.log(LogInfo, "Delete conflict, retry", "account_id", accountId, "version", version, "attempt", attempt)
+		conflict := &VersionConflict{AccountId: accountId, Version: version, Attempts: attempt}
+		lastConflict = err{code: ErrConflict, msg: conflict.Error(), cause: conflict, req: er.Request(), resp: er.Response()}
+	}
+	return lastConflict
+}
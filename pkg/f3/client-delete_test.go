@@ -0,0 +1,83 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestClient_DeleteAccountLatest(t *testing.T) {
+	var (
+		fetches int
+		deleted bool
+	)
+	account := createTestAccount(true)
+	version := uint64(0)
+	account.Version = &version
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if deleted {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			fetches++
+			_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+			if fetches == 1 {
+				// Simulate a concurrent modification after the first fetch.
+				concurrent := *account.Version + 1
+				account.Version = &concurrent
+			}
+		case http.MethodDelete:
+			if r.URL.Query().Get("version") != strconv.FormatUint(*account.Version, 10) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	if e := client.DeleteAccountLatest(account.Id, f3.DeleteExisting); e != nil {
+		t.Fatalf("Failed to delete the account: %s", e.Error())
+	}
+	if fetches != 2 || !deleted {
+		t.Errorf("Expected the account to be deleted after 2 fetches, but fetched %d times", fetches)
+	}
+	if e := client.DeleteAccountLatest(account.Id, f3.EnsureAbsent); e != nil {
+		t.Errorf("Ensuring that a deleted account is absent must not fail, got: %s", e.Error())
+	}
+	if e := client.DeleteAccountLatest(account.Id, f3.DeleteExisting); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected the deleted account to be not found, got: %v", e)
+	}
+}
+
+func TestClient_DeleteAccountLatest_FetchConflict(t *testing.T) {
+	var deletes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deletes++
+		}
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(f3.NoRetry))
+
+	e := client.DeleteAccountLatest(f3.IntegrationTestAccountId, f3.DeleteExisting)
+	if e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Fatalf("Expected the conflict of the fetch to be returned, got: %v", e)
+	}
+	var conflict *f3.VersionConflict
+	if errors.As(e, &conflict) {
+		t.Errorf("A failed fetch must be returned unchanged, but got a version conflict: %s", e.Error())
+	}
+	if deletes != 0 {
+		t.Errorf("Expected no delete after the failed fetch, but %d were sent", deletes)
+	}
+}
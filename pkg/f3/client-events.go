@@ -0,0 +1,203 @@
+package f3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval in which WatchAccountEvents polls for new events.
+var DefaultWatchInterval = 5 * time.Second
+
+// ListAccountEvents returns a single page of the events of the account with the given id. If no page is given, the
+// first page with the default size of the account API is returned. The links of the returned envelope refer to the
+// other pages.
+func (c *Client) ListAccountEvents(accountId string, page *Page) (*AccountEventsEnvelope, Err) {
+	return c.ListAccountEventsWithContext(context.Background(), accountId, page)
+}
+
+// ListAccountEventsWithContext is like ListAccountEvents, but the request is bound to the given context. If the context
+// is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) ListAccountEventsWithContext(ctx context.Context, accountId string, page *Page) (*AccountEventsEnvelope, Err) {
+	return c.listAccountEvents(ctx, accountId, c.accountEventsUri(accountId, page))
+}
+
+// accountEventsUri returns the uri of the given page of the events of the account.
+func (c *Client) accountEventsUri(accountId string, page *Page) string {
+	uri := fmt.Sprintf("%s/%s/events", c.accountUri, url.QueryEscape(accountId))
+	query := url.Values{}
+	page.encode(query)
+	if len(query) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, query.Encode())
+	}
+	return uri
+}
+
+// listAccountEvents lists the account events from the given uri.
+func (c *Client) listAccountEvents(ctx context.Context, accountId string, uri string) (*AccountEventsEnvelope, Err) {
+	ctx, end := c.begin(ctx, opListAccountEvents, "account_id", accountId)
+	var envelope AccountEventsEnvelope
+	er := call(ctx, c, opListAccountEvents, http.MethodGet, uri, (*any)(nil), &envelope)
+	end(er)
+	if er != nil {
+		return nil, er
+	}
+	return &envelope, nil
+}
+
+// AccountEventIterator iterates over all events of an account.
+type AccountEventIterator struct {
+	Iterator[*AccountEvent]
+}
+
+// IterateAccountEvents returns an iterator over all events of the account with the given id, fetching pages of the
+// given size.
+func (c *Client) IterateAccountEvents(ctx context.Context, accountId string, pageSize int) *AccountEventIterator {
+	return c.iterateAccountEvents(ctx, accountId, c.accountEventsUri(accountId, &Page{Size: pageSize}))
+}
+
+// iterateAccountEvents returns an iterator over the events of the account starting with the page of the given uri.
+func (c *Client) iterateAccountEvents(ctx context.Context, accountId string, uri string) *AccountEventIterator {
+	return &AccountEventIterator{newIterator(ctx, c, uri, func(ctx context.Context, uri string) ([]*AccountEvent, *Links, Err) {
+		envelope, er := c.listAccountEvents(ctx, accountId, uri)
+		if er != nil {
+			return nil, nil, er
+		}
+		return envelope.Data, envelope.Links, nil
+	})}
+}
+
+// Event returns the current event.
+func (it *AccountEventIterator) Event() *AccountEvent {
+	return it.Value()
+}
+
+// WatchOptions configure WatchAccountEvents.
+type WatchOptions struct {
+	// Interval is the time between two polls; zero selects the DefaultWatchInterval.
+	Interval time.Duration
+
+	// Cursor is the id of the last event already processed, only the events after it are delivered. If empty, all
+	// events of the account are delivered. Use AccountEventWatch.Cursor to resume a watch. If the cursor is not found
+	// in the events of the account, the watch stops with ErrResponse.
+	Cursor string
+
+	// PageSize is the size of the pages to fetch the events with; zero selects the default of the account API.
+	PageSize int
+}
+
+// AccountEventWatch delivers the new events of an account, until the context of the watch is done or the account
+// does not exist anymore.
+type AccountEventWatch struct {
+	events chan *AccountEvent
+	mutex  sync.Mutex
+	cursor string
+	er     Err
+
+	// position is the uri of the page holding the cursor, at which the next poll starts.
+	position string
+}
+
+// errCursorLost is the cause of the error returned, when the cursor of a watch is not found in the events.
+var errCursorLost = errors.New("cursor not found in the events of the account")
+
+// WatchAccountEvents starts to poll the events of the account with the given id in the background and delivers new
+// events in order on the channel returned by AccountEventWatch.Events. The channel is closed, when the given context
+// is done, the account does not exist anymore or the cursor is lost. Failed polls are logged and retried in the next
+// interval, the last error is available from AccountEventWatch.Err. Every poll resumes at the page of the cursor, so
+// only the first one of a watch with a cursor walks through the pages before it.
+func (c *Client) WatchAccountEvents(ctx context.Context, accountId string, opts WatchOptions) *AccountEventWatch {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	w := &AccountEventWatch{
+		events:   make(chan *AccountEvent),
+		cursor:   opts.Cursor,
+		position: c.accountEventsUri(accountId, &Page{Size: opts.PageSize}),
+	}
+	go w.run(ctx, c, accountId, opts)
+	return w
+}
+
+// Events returns the channel on which the new events are delivered.
+func (w *AccountEventWatch) Events() <-chan *AccountEvent {
+	return w.events
+}
+
+// Cursor returns the id of the last delivered event, which can be used to resume watching later.
+func (w *AccountEventWatch) Cursor() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.cursor
+}
+
+// Err returns the error of the last failed poll, if any, even if later polls succeeded.
+func (w *AccountEventWatch) Err() Err {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.er
+}
+
+// run polls the events until the context is done.
+func (w *AccountEventWatch) run(ctx context.Context, c *Client, accountId string, opts WatchOptions) {
+	defer close(w.events)
+	for {
+		events, position, er := w.poll(ctx, c, accountId)
+		if er != nil {
+			w.mutex.Lock()
+			w.er = er
+			w.mutex.Unlock()
+			if er.ErrorCode() == ErrNotFound || errors.Is(er, errCursorLost) || ctx.Err() != nil {
+				return
+			}
+			c.log(LogWarn, "Polling account events failed", "account_id", accountId, "error", er.Error())
+		}
+		for _, event := range events {
+			select {
+			case w.events <- event:
+				w.mutex.Lock()
+				w.cursor = event.Id
+				w.mutex.Unlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+		if er == nil {
+			w.position = position
+		}
+		if sleep(ctx, opts.Interval) != nil {
+			return
+		}
+	}
+}
+
+// poll returns the events after the cursor and the uri of the page holding the last of them. The events are fetched
+// starting with the page of the cursor. If fetching any page fails, no events are returned, because the cursor may be
+// on the missing page. If the cursor is not found, an error is returned instead of delivering all events again.
+func (w *AccountEventWatch) poll(ctx context.Context, c *Client, accountId string) ([]*AccountEvent, string, Err) {
+	cursor := w.Cursor()
+	position := w.position
+	found := len(cursor) == 0
+	var events []*AccountEvent
+	it := c.iterateAccountEvents(ctx, accountId, position)
+	for it.Next() {
+		event := it.Event()
+		position = it.current
+		if found {
+			events = append(events, event)
+		} else if event.Id == cursor {
+			found = true
+		}
+	}
+	if it.Err() != nil {
+		return nil, "", it.Err()
+	}
+	if !found {
+		return nil, "", err{code: ErrResponse, msg: fmt.Sprintf("The cursor %q was not found", cursor), cause: errCursorLost}
+	}
+	return events, position, nil
+}
@@ -0,0 +1,140 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// eventServer is a test server holding the events of a single account.
+type eventServer struct {
+	*httptest.Server
+	mutex  sync.Mutex
+	events []*f3.AccountEvent
+	pages  []int
+}
+
+func newEventServer(accountId string) *eventServer {
+	s := &eventServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/organisation/accounts/"+accountId+"/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+		s.pages = append(s.pages, number)
+		envelope := f3.AccountEventsEnvelope{Data: []*f3.AccountEvent{}, Links: &f3.Links{}}
+		for i := number * size; i < len(s.events) && i < (number+1)*size; i++ {
+			envelope.Data = append(envelope.Data, s.events[i])
+		}
+		if (number+1)*size < len(s.events) {
+			envelope.Links.Next = fmt.Sprintf("%s?page[number]=%d&page[size]=%d", r.URL.Path, number+1, size)
+		}
+		_ = json.NewEncoder(w).Encode(&envelope)
+	}))
+	return s
+}
+
+func (s *eventServer) add(accountId string, status f3.AccountStatusString) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	event := &f3.AccountEvent{Attr: &f3.AccountEventAttr{AccountId: accountId, DateTime: &now, Status: status}}
+	event.Id = fmt.Sprintf("event-%d", len(s.events))
+	event.Type = f3.TypeAccountEvent
+	s.events = append(s.events, event)
+}
+
+func TestClient_IterateAccountEvents(t *testing.T) {
+	server := newEventServer(f3.IntegrationTestAccountId)
+	defer server.Close()
+	for i := 0; i < 3; i++ {
+		server.add(f3.IntegrationTestAccountId, f3.StatusPending)
+	}
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	it := client.IterateAccountEvents(context.Background(), f3.IntegrationTestAccountId, 2)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Event().Id)
+	}
+	if it.Err() != nil {
+		t.Fatalf("Failed to iterate the events: %s", it.Err().Error())
+	}
+	if fmt.Sprint(ids) != "[event-0 event-1 event-2]" {
+		t.Errorf("Iterated the wrong events: %v", ids)
+	}
+}
+
+func TestClient_WatchAccountEvents(t *testing.T) {
+	server := newEventServer(f3.IntegrationTestAccountId)
+	defer server.Close()
+	server.add(f3.IntegrationTestAccountId, f3.StatusPending)
+	server.add(f3.IntegrationTestAccountId, f3.StatusPending)
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watch := client.WatchAccountEvents(ctx, f3.IntegrationTestAccountId, f3.WatchOptions{
+		Interval: 10 * time.Millisecond,
+		Cursor:   "event-0",
+		PageSize: 1,
+	})
+	event := <-watch.Events()
+	if event == nil || event.Id != "event-1" {
+		t.Fatalf("Expected the event after the cursor, got: %v", event)
+	}
+	server.add(f3.IntegrationTestAccountId, f3.StatusConfirmed)
+	event = <-watch.Events()
+	if event == nil || event.Id != "event-2" || event.Attr.Status != f3.StatusConfirmed {
+		t.Fatalf("Expected the new confirmed event, got: %v", event)
+	}
+	cancel()
+	for range watch.Events() {
+		t.Errorf("Received an event after the watch was canceled")
+	}
+	if watch.Cursor() != "event-2" {
+		t.Errorf("Expected the cursor to be at the last event, but is %s", watch.Cursor())
+	}
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for i, number := range server.pages {
+		if number == 0 && i > 0 {
+			t.Fatalf("Expected the polls to resume at the page of the cursor, but fetched the pages %v", server.pages)
+		}
+	}
+}
+
+func TestClient_WatchAccountEvents_LostCursor(t *testing.T) {
+	server := newEventServer(f3.IntegrationTestAccountId)
+	defer server.Close()
+	server.add(f3.IntegrationTestAccountId, f3.StatusPending)
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watch := client.WatchAccountEvents(ctx, f3.IntegrationTestAccountId, f3.WatchOptions{
+		Interval: 10 * time.Millisecond,
+		Cursor:   "unknown",
+		PageSize: 1,
+	})
+	for event := range watch.Events() {
+		t.Errorf("Expected no event for a lost cursor, got: %s", event.Id)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("Expected the watch to stop, when the cursor is lost")
+	}
+	if watch.Err() == nil || watch.Err().ErrorCode() != f3.ErrResponse {
+		t.Errorf("Expected the lost cursor to be reported, got: %v", watch.Err())
+	}
+}
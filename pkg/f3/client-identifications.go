@@ -0,0 +1,187 @@
+package f3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AccountIdentificationFilter filters the account identifications to list. Every field holds the values to accept,
+// an identification must match at least one value of every non-empty field.
+type AccountIdentificationFilter struct {
+	OrganisationId          []string
+	SecondaryIdentification []string
+}
+
+// encode adds the filter to the given query.
+func (f *AccountIdentificationFilter) encode(query url.Values) {
+	if f == nil {
+		return
+	}
+	if len(f.OrganisationId) > 0 {
+		query.Set("filter[organisation_id]", strings.Join(f.OrganisationId, ","))
+	}
+	if len(f.SecondaryIdentification) > 0 {
+		query.Set("filter[secondary_identification]", strings.Join(f.SecondaryIdentification, ","))
+	}
+}
+
+// identificationsUri returns the uri of the identifications of the given account.
+func (c *Client) identificationsUri(accountId string) string {
+	return fmt.Sprintf("%s/%s/identifications", c.accountUri, url.QueryEscape(accountId))
+}
+
+// identificationUri returns the uri of the given identification of the given account.
+func (c *Client) identificationUri(accountId string, identificationId string) string {
+	return fmt.Sprintf("%s/%s", c.identificationsUri(accountId), url.QueryEscape(identificationId))
+}
+
+// ListAccountIdentifications returns a single page of the identifications of the account with the given id, that
+// match the given filter. If no filter is given, all identifications are listed, if no page is given, the first page
+// with the default size of the account API is returned.
+func (c *Client) ListAccountIdentifications(accountId string, filter *AccountIdentificationFilter, page *Page) (*AccountIdentificationsEnvelope, Err) {
+	return c.ListAccountIdentificationsWithContext(context.Background(), accountId, filter, page)
+}
+
+// ListAccountIdentificationsWithContext is like ListAccountIdentifications, but the request is bound to the given
+// context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively
+// ErrTimeout is returned.
+func (c *Client) ListAccountIdentificationsWithContext(ctx context.Context, accountId string, filter *AccountIdentificationFilter, page *Page) (*AccountIdentificationsEnvelope, Err) {
+	return c.listAccountIdentifications(ctx, accountId, c.listIdentificationsUri(accountId, filter, page))
+}
+
+// listIdentificationsUri returns the uri of the given page of the identifications of the account matching the filter.
+func (c *Client) listIdentificationsUri(accountId string, filter *AccountIdentificationFilter, page *Page) string {
+	uri := c.identificationsUri(accountId)
+	query := url.Values{}
+	filter.encode(query)
+	page.encode(query)
+	if len(query) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, query.Encode())
+	}
+	return uri
+}
+
+// listAccountIdentifications lists the account identifications from the given uri.
+func (c *Client) listAccountIdentifications(ctx context.Context, accountId string, uri string) (*AccountIdentificationsEnvelope, Err) {
+	ctx, end := c.begin(ctx, opListAccountIdentifications, "account_id", accountId)
+	var envelope AccountIdentificationsEnvelope
+	er := call(ctx, c, opListAccountIdentifications, http.MethodGet, uri, (*any)(nil), &envelope)
+	end(er)
+	if er != nil {
+		return nil, er
+	}
+	return &envelope, nil
+}
+
+// AccountIdentificationIterator iterates over the identifications of an account.
+type AccountIdentificationIterator struct {
+	Iterator[*AccountIdentification]
+}
+
+// IterateAccountIdentifications returns an iterator over all identifications of the account with the given id, that
+// match the given filter, fetching pages of the given size.
+func (c *Client) IterateAccountIdentifications(ctx context.Context, accountId string, filter *AccountIdentificationFilter, pageSize int) *AccountIdentificationIterator {
+	uri := c.listIdentificationsUri(accountId, filter, &Page{Size: pageSize})
+	return &AccountIdentificationIterator{newIterator(ctx, c, uri, func(ctx context.Context, uri string) ([]*AccountIdentification, *Links, Err) {
+		envelope, er := c.listAccountIdentifications(ctx, accountId, uri)
+		if er != nil {
+			return nil, nil, er
+		}
+		return envelope.Data, envelope.Links, nil
+	})}
+}
+
+// Identification returns the current identification.
+func (it *AccountIdentificationIterator) Identification() *AccountIdentification {
+	return it.Value()
+}
+
+// CreateAccountIdentification attaches the given identification to the account with the given id and returns the
+// created identification. If the secondary identification is already used, ErrConflict is returned.
+func (c *Client) CreateAccountIdentification(accountId string, identification *AccountIdentification) (*AccountIdentification, Err) {
+	return c.CreateAccountIdentificationWithContext(context.Background(), accountId, identification)
+}
+
+// CreateAccountIdentificationWithContext is like CreateAccountIdentification, but the request is bound to the given
+// context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively
+// ErrTimeout is returned.
+func (c *Client) CreateAccountIdentificationWithContext(ctx context.Context, accountId string, identification *AccountIdentification) (*AccountIdentification, Err) {
+	ctx, end := c.begin(ctx, opCreateAccountIdentification, "account_id", accountId)
+	var er Err
+	var envelope AccountIdentificationEnvelope
+	if identification == nil {
+		er = err{code: ErrRequest, msg: "No identification given"}
+	} else {
+		if len(identification.OrganisationId) == 0 && len(c.organisationId) > 0 {
+			withOrganisation := *identification
+			withOrganisation.OrganisationId = c.organisationId
+			identification = &withOrganisation
+		}
+		request := AccountIdentificationEnvelope{identification}
+		er = call(ctx, c, opCreateAccountIdentification, http.MethodPost, c.identificationsUri(accountId), &request, &envelope)
+	}
+	created, er := present(envelope.Data, er)
+	end(er)
+	return created, er
+}
+
+// FetchAccountIdentification returns the identification with the given id of the account with the given id or
+// ErrNotFound, if it does not exist.
+func (c *Client) FetchAccountIdentification(accountId string, identificationId string) (*AccountIdentification, Err) {
+	return c.FetchAccountIdentificationWithContext(context.Background(), accountId, identificationId)
+}
+
+// FetchAccountIdentificationWithContext is like FetchAccountIdentification, but the request is bound to the given
+// context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively
+// ErrTimeout is returned.
+func (c *Client) FetchAccountIdentificationWithContext(ctx context.Context, accountId string, identificationId string) (*AccountIdentification, Err) {
+	ctx, end := c.begin(ctx, opFetchAccountIdentification, "account_id", accountId, "identification_id", identificationId)
+	var envelope AccountIdentificationEnvelope
+	er := call(ctx, c, opFetchAccountIdentification, http.MethodGet, c.identificationUri(accountId, identificationId), (*any)(nil), &envelope)
+	fetched, er := present(envelope.Data, er)
+	end(er)
+	return fetched, er
+}
+
+// PatchAccountIdentification changes the attributes of the identification with the given id and returns the updated
+// identification with its new version. If the version does not match the current version of the identification or the
+// secondary identification is already used, ErrConflict is returned.
+func (c *Client) PatchAccountIdentification(accountId string, identificationId string, version uint64, changes *AccountIdentificationAttr) (*AccountIdentification, Err) {
+	return c.PatchAccountIdentificationWithContext(context.Background(), accountId, identificationId, version, changes)
+}
+
+// PatchAccountIdentificationWithContext is like PatchAccountIdentification, but the request is bound to the given
+// context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively
+// ErrTimeout is returned.
+func (c *Client) PatchAccountIdentificationWithContext(ctx context.Context, accountId string, identificationId string, version uint64, changes *AccountIdentificationAttr) (*AccountIdentification, Err) {
+	ctx, end := c.begin(ctx, opPatchAccountIdentification, "account_id", accountId, "identification_id", identificationId, "version", version)
+	patch := AccountIdentification{Attr: changes}
+	patch.Id = identificationId
+	patch.Type = TypeAccountIdentification
+	patch.Version = &version
+	var envelope AccountIdentificationEnvelope
+	er := call(ctx, c, opPatchAccountIdentification, http.MethodPatch, c.identificationUri(accountId, identificationId), &AccountIdentificationEnvelope{&patch}, &envelope)
+	patched, er := present(envelope.Data, er)
+	end(er)
+	return patched, er
+}
+
+// DeleteAccountIdentification deletes the identification with the given id from the account with the given id. If
+// the identification does not exist, ErrNotFound is returned, if the version does not match, ErrConflict.
+func (c *Client) DeleteAccountIdentification(accountId string, identificationId string, version uint64) Err {
+	return c.DeleteAccountIdentificationWithContext(context.Background(), accountId, identificationId, version)
+}
+
+// DeleteAccountIdentificationWithContext is like DeleteAccountIdentification, but the request is bound to the given
+// context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively
+// ErrTimeout is returned.
+func (c *Client) DeleteAccountIdentificationWithContext(ctx context.Context, accountId string, identificationId string, version uint64) Err {
+	ctx, end := c.begin(ctx, opDeleteAccountIdentification, "account_id", accountId, "identification_id", identificationId, "version", version)
+	uri := fmt.Sprintf("%s?version=%d", c.identificationUri(accountId, identificationId), version)
+	er := call(ctx, c, opDeleteAccountIdentification, http.MethodDelete, uri, (*any)(nil), (*any)(nil))
+	end(er)
+	return er
+}
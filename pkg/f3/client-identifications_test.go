@@ -0,0 +1,145 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newIdentificationServer returns a test server that stores the identifications of the given account in memory.
+func newIdentificationServer(t *testing.T, accountId string) *httptest.Server {
+	var mutex sync.Mutex
+	identifications := map[string]*f3.AccountIdentification{}
+	prefix := "/v1/organisation/accounts/" + accountId + "/identifications"
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		var request f3.AccountIdentificationEnvelope
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&request)
+		}
+		switch {
+		case id == "" && r.Method == http.MethodGet:
+			envelope := f3.AccountIdentificationsEnvelope{Data: []*f3.AccountIdentification{}, Links: &f3.Links{}}
+			accept := r.URL.Query().Get("filter[secondary_identification]")
+			for _, identification := range identifications {
+				if accept == "" || accept == identification.Attr.SecondaryIdentification {
+					envelope.Data = append(envelope.Data, identification)
+				}
+			}
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case id == "" && r.Method == http.MethodPost:
+			for _, identification := range identifications {
+				if identification.Attr.SecondaryIdentification == request.Data.Attr.SecondaryIdentification {
+					w.WriteHeader(http.StatusConflict)
+					_, _ = w.Write([]byte(`{"error_message":"secondary identification already in use"}`))
+					return
+				}
+			}
+			created := request.Data
+			version := uint64(0)
+			created.Version = &version
+			created.Relationships = &f3.AccountIdentificationRelationships{
+				Account: &f3.Relationship{Data: []f3.RelationshipData{{Id: accountId, Type: f3.TypeAccount}}},
+			}
+			identifications[created.Id] = created
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&f3.AccountIdentificationEnvelope{Data: created})
+		case identifications[id] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(&f3.AccountIdentificationEnvelope{Data: identifications[id]})
+		case r.Method == http.MethodPatch:
+			stored := identifications[id]
+			if *request.Data.Version != *stored.Version {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			version := *stored.Version + 1
+			stored.Version = &version
+			stored.Attr.SecondaryIdentification = request.Data.Attr.SecondaryIdentification
+			_ = json.NewEncoder(w).Encode(&f3.AccountIdentificationEnvelope{Data: stored})
+		case r.Method == http.MethodDelete:
+			if r.URL.Query().Get("version") != strconv.FormatUint(*identifications[id].Version, 10) {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			delete(identifications, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestClient_AccountIdentifications(t *testing.T) {
+	accountId := f3.IntegrationTestAccountId
+	server := newIdentificationServer(t, accountId)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+	identification := f3.NewAccountIdentification(nil, "REF-0001")
+	created, e := client.CreateAccountIdentification(accountId, identification)
+	if e != nil {
+		t.Fatalf("Failed to create the identification: %s", e.Error())
+	}
+	if created.Id != identification.Id || created.Relationships == nil || created.Relationships.Account.Data[0].Id != accountId {
+		t.Errorf("Created the wrong identification: %v", created)
+	}
+	_, e = client.CreateAccountIdentification(accountId, f3.NewAccountIdentification(nil, "REF-0001"))
+	if e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for a duplicate secondary identification, but got: %v", e)
+	}
+
+	fetched, e := client.FetchAccountIdentification(accountId, identification.Id)
+	if e != nil || fetched.Attr.SecondaryIdentification != "REF-0001" {
+		t.Fatalf("Failed to fetch the identification: %v", e)
+	}
+	_, e = client.FetchAccountIdentification(accountId, "unknown")
+	if e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected not found for an unknown identification, but got: %v", e)
+	}
+
+	patched, e := client.PatchAccountIdentification(accountId, identification.Id, 0, &f3.AccountIdentificationAttr{SecondaryIdentification: "REF-0002"})
+	if e != nil {
+		t.Fatalf("Failed to patch the identification: %s", e.Error())
+	}
+	if *patched.Version != 1 || patched.Attr.SecondaryIdentification != "REF-0002" {
+		t.Errorf("Patched the wrong identification: %v", patched)
+	}
+	_, e = client.PatchAccountIdentification(accountId, identification.Id, 0, &f3.AccountIdentificationAttr{SecondaryIdentification: "REF-0003"})
+	if e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for an outdated version, but got: %v", e)
+	}
+
+	envelope, e := client.ListAccountIdentifications(accountId, &f3.AccountIdentificationFilter{SecondaryIdentification: []string{"REF-0002"}}, nil)
+	if e != nil || len(envelope.Data) != 1 {
+		t.Fatalf("Failed to list the identifications: %v", e)
+	}
+
+	if e = client.DeleteAccountIdentification(accountId, identification.Id, 0); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict when deleting an outdated version, but got: %v", e)
+	}
+	if e = client.DeleteAccountIdentification(accountId, identification.Id, 1); e != nil {
+		t.Fatalf("Failed to delete the identification: %s", e.Error())
+	}
+	it := client.IterateAccountIdentifications(context.Background(), accountId, nil, 10)
+	for it.Next() {
+		t.Errorf("Expected no identifications after the delete, but got: %v", it.Identification())
+	}
+	if it.Err() != nil {
+		t.Errorf("Failed to iterate the identifications: %s", it.Err().Error())
+	}
+}
@@ -0,0 +1,226 @@
+package f3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxPageSize is the maximal amount of items the account API returns per page.
+const MaxPageSize = 1000
+
+// AccountFilter filters the accounts to list. Every field holds the values to accept, an account must match at least
+// one value of every non-empty field.
+type AccountFilter struct {
+	OrganisationId []string
+	BankIdCode     []string
+	BankId         []string
+	AccountNumber  []string
+	Country        []string
+	CustomerId     []string
+	Iban           []string
+}
+
+// Page selects a single page of a list.
+type Page struct {
+	// Number is the number of the page, starting with 0.
+	Number int
+
+	// Size is the amount of items per page, at most MaxPageSize; zero selects the default size of the account API.
+	Size int
+}
+
+// encode adds the filter to the given query.
+func (f *AccountFilter) encode(query url.Values) {
+	if f == nil {
+		return
+	}
+	for name, values := range map[string][]string{
+		"organisation_id": f.OrganisationId,
+		"bank_id_code":    f.BankIdCode,
+		"bank_id":         f.BankId,
+		"account_number":  f.AccountNumber,
+		"country":         f.Country,
+		"customer_id":     f.CustomerId,
+		"iban":            f.Iban,
+	} {
+		if len(values) > 0 {
+			query.Set(fmt.Sprintf("filter[%s]", name), strings.Join(values, ","))
+		}
+	}
+}
+
+// encode adds the page to the given query.
+func (p *Page) encode(query url.Values) {
+	if p == nil {
+		return
+	}
+	query.Set("page[number]", strconv.Itoa(p.Number))
+	if p.Size > 0 {
+		size := p.Size
+		if size > MaxPageSize {
+			size = MaxPageSize
+		}
+		query.Set("page[size]", strconv.Itoa(size))
+	}
+}
+
+// ListAccounts returns a single page of the accounts matching the given filter. If no filter is given, all accounts are
+// listed, if no page is given, the first page with the default size of the account API is returned. The links of the
+// returned envelope refer to the other pages.
+func (c *Client) ListAccounts(filter *AccountFilter, page *Page) (*AccountsEnvelope, Err) {
+	return c.ListAccountsWithContext(context.Background(), filter, page)
+}
+
+// ListAccountsWithContext is like ListAccounts, but the request is bound to the given context. If the context is
+// canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) ListAccountsWithContext(ctx context.Context, filter *AccountFilter, page *Page) (*AccountsEnvelope, Err) {
+	query := url.Values{}
+	filter.encode(query)
+	page.encode(query)
+	uri := c.accountUri
+	if len(query) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, query.Encode())
+	}
+	return c.listAccounts(ctx, uri)
+}
+
+// listAccounts lists the accounts from the given uri.
+func (c *Client) listAccounts(ctx context.Context, uri string) (*AccountsEnvelope, Err) {
+	ctx, end := c.begin(ctx, opListAccounts)
+	var envelope AccountsEnvelope
+	er := call(ctx, c, opListAccounts, http.MethodGet, uri, (*any)(nil), &envelope)
+	end(er)
+	if er != nil {
+		return nil, er
+	}
+	return &envelope, nil
+}
+
+// resolve resolves the given link, which may be relative to the endpoint, into an absolute uri. Links to another
+// scheme or host than the one of the endpoint are rejected, so that the client never sends requests elsewhere.
+func (c *Client) resolve(link string) (string, error) {
+	base, e := url.Parse(c.endpoint)
+	if e != nil {
+		return "", e
+	}
+	ref, e := url.Parse(link)
+	if e != nil {
+		return "", e
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+		return "", fmt.Errorf("the link refers to %s://%s instead of the endpoint", resolved.Scheme, resolved.Host)
+	}
+	return resolved.String(), nil
+}
+
+// Iterator iterates over all items of a paginated list, fetching the pages on demand by following the next links,
+// until a page is empty or has no next link. A link to another scheme or host than the one of the endpoint stops the
+// iteration with ErrResponse after the items of the page holding it.
+type Iterator[T any] struct {
+	ctx     context.Context
+	client  *Client
+	list    func(ctx context.Context, uri string) ([]T, *Links, Err)
+	next    string
+	current string
+	visited map[string]bool
+	page    []T
+	index   int
+	value   T
+	er      Err
+
+	// invalidNext is the error of an invalid link to the next page, reported after the items of the current page.
+	invalidNext Err
+}
+
+// newIterator creates an iterator starting with the given uri and fetching the pages using the given function.
+func newIterator[T any](ctx context.Context, c *Client, uri string, list func(context.Context, string) ([]T, *Links, Err)) Iterator[T] {
+	return Iterator[T]{ctx: ctx, client: c, list: list, next: uri, visited: map[string]bool{}}
+}
+
+// Next advances the iterator to the next item and returns true, if there is one. It returns false, when all items
+// were returned or an error occurred, see Err.
+func (it *Iterator[T]) Next() bool {
+	var zero T
+	for it.er == nil {
+		if it.index < len(it.page) {
+			it.value = it.page[it.index]
+			it.index++
+			return true
+		}
+		if it.invalidNext != nil {
+			it.er = it.invalidNext
+			break
+		}
+		if len(it.next) == 0 {
+			break
+		}
+		uri := it.next
+		it.visited[uri] = true
+		page, links, er := it.list(it.ctx, uri)
+		if er != nil {
+			it.er = er
+			break
+		}
+		it.page, it.index, it.next, it.current = page, 0, "", uri
+		if links != nil && len(links.Next) > 0 && len(page) > 0 {
+			next, e := it.client.resolve(links.Next)
+			if e != nil {
+				it.invalidNext = err{code: ErrResponse, msg: "Invalid link to the next page", cause: e}
+				continue
+			}
+			if !it.visited[next] {
+				it.next = next
+			}
+		}
+	}
+	it.value = zero
+	return false
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() Err {
+	return it.er
+}
+
+// AccountIterator iterates over all accounts of a list.
+//
+//	it := client.IterateAccounts(ctx, filter, 100)
+//	for it.Next() {
+//		account := it.Account()
+//	}
+//	if it.Err() != nil {
+//		...
+//	}
+type AccountIterator struct {
+	Iterator[*Account]
+}
+
+// IterateAccounts returns an iterator over all accounts matching the given filter, fetching pages of the given size.
+func (c *Client) IterateAccounts(ctx context.Context, filter *AccountFilter, pageSize int) *AccountIterator {
+	query := url.Values{}
+	filter.encode(query)
+	(&Page{Size: pageSize}).encode(query)
+	uri := fmt.Sprintf("%s?%s", c.accountUri, query.Encode())
+	return &AccountIterator{newIterator(ctx, c, uri, func(ctx context.Context, uri string) ([]*Account, *Links, Err) {
+		envelope, er := c.listAccounts(ctx, uri)
+		if er != nil {
+			return nil, nil, er
+		}
+		return envelope.Data, envelope.Links, nil
+	})}
+}
+
+// Account returns the current account.
+func (it *AccountIterator) Account() *Account {
+	return it.Value()
+}
@@ -0,0 +1,97 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newListServer returns a test server that lists the given amount of accounts in pages, linking the next page
+// relative to the server.
+func newListServer(t *testing.T, total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("filter[country]") != "GB,DE" {
+			t.Errorf("Expected the country filter GB,DE, but got: %s", query.Get("filter[country]"))
+		}
+		number, _ := strconv.Atoi(query.Get("page[number]"))
+		size, _ := strconv.Atoi(query.Get("page[size]"))
+		envelope := f3.AccountsEnvelope{Data: []*f3.Account{}, Links: &f3.Links{Self: r.URL.String()}}
+		for i := number * size; i < total && i < (number+1)*size; i++ {
+			account := createTestAccount(false)
+			account.Id = fmt.Sprintf("account-%d", i)
+			envelope.Data = append(envelope.Data, account)
+		}
+		if (number+1)*size < total {
+			envelope.Links.Next = fmt.Sprintf("/v1/organisation/accounts?filter%%5Bcountry%%5D=GB,DE&page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=%d", number+1, size)
+		}
+		_ = json.NewEncoder(w).Encode(&envelope)
+	}))
+}
+
+func TestClient_ListAccounts(t *testing.T) {
+	server := newListServer(t, 5)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+	filter := &f3.AccountFilter{Country: []string{"GB", "DE"}}
+	envelope, e := client.ListAccounts(filter, &f3.Page{Number: 1, Size: 2})
+	if e != nil {
+		t.Fatalf("Failed to list the accounts: %s", e.Error())
+	}
+	if len(envelope.Data) != 2 || envelope.Data[0].Id != "account-2" {
+		t.Errorf("Listed the wrong page: %v", envelope.Data)
+	}
+	if envelope.Links == nil || envelope.Links.Next == "" {
+		t.Errorf("Expected a link to the next page, got: %v", envelope.Links)
+	}
+}
+
+func TestClient_IterateAccounts(t *testing.T) {
+	server := newListServer(t, 5)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+	filter := &f3.AccountFilter{Country: []string{"GB", "DE"}}
+	it := client.IterateAccounts(context.Background(), filter, 2)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Account().Id)
+	}
+	if it.Err() != nil {
+		t.Fatalf("Failed to iterate the accounts: %s", it.Err().Error())
+	}
+	if fmt.Sprint(ids) != "[account-0 account-1 account-2 account-3 account-4]" {
+		t.Errorf("Iterated the wrong accounts: %v", ids)
+	}
+}
+
+func TestClient_IterateAccounts_ForeignLink(t *testing.T) {
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("The iterator followed a link to another host: %s", r.URL)
+	}))
+	defer foreign.Close()
+	for _, next := range []string{foreign.URL + "/v1/organisation/accounts?page%5Bnumber%5D=1", "//example.com/v1/accounts"} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			account := createTestAccount(false)
+			envelope := f3.AccountsEnvelope{Data: []*f3.Account{account}, Links: &f3.Links{Next: next}}
+			_ = json.NewEncoder(w).Encode(&envelope)
+		}))
+		client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+		it := client.IterateAccounts(context.Background(), nil, 1)
+		count := 0
+		for it.Next() {
+			count++
+		}
+		server.Close()
+		if count != 1 || it.Err() == nil || it.Err().ErrorCode() != f3.ErrResponse {
+			t.Errorf("Expected the iteration to stop at the link %s, got %d accounts and: %v", next, count, it.Err())
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package f3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+)
+
+// accountPatchEnvelope is the envelope to patch an account.
+type accountPatchEnvelope struct {
+	Data *accountPatch `json:"data"`
+}
+
+// accountPatch holds the attributes to change, only the attributes present are modified by the account API.
+type accountPatch struct {
+	Resource
+	Attr map[string]any `json:"attributes"`
+}
+
+// AccountChanges are the changes to patch the attributes of an account with. Only the non-zero fields of Attr are
+// sent, so the flags, of which false is the zero value, are changed using the pointer fields instead.
+type AccountChanges struct {
+	// Attr holds the attributes to change, only its non-zero fields are sent.
+	Attr *AccountAttr
+
+	// Switched, if not nil, changes the attribute switched to the given value.
+	Switched *bool
+
+	// AccountMatchingOptOut, if not nil, changes the attribute account_matching_opt_out to the given value.
+	AccountMatchingOptOut *bool
+
+	// JointAccount, if not nil, changes the attribute joint_account to the given value.
+	JointAccount *bool
+}
+
+// attributes returns the attributes to send for the changes.
+func (changes *AccountChanges) attributes() map[string]any {
+	attributes := map[string]any{}
+	if changes == nil {
+		return attributes
+	}
+	if !toJsonMap(changes.Attr, &attributes) || attributes == nil {
+		attributes = map[string]any{}
+	}
+	for name, flag := range map[string]*bool{
+		"switched":                 changes.Switched,
+		"account_matching_opt_out": changes.AccountMatchingOptOut,
+		"joint_account":            changes.JointAccount,
+	} {
+		if flag != nil {
+			attributes[name] = *flag
+		}
+	}
+	return attributes
+}
+
+// PatchAccount changes the attributes of the account with the given id and returns the updated account with its new
+// version. Only the non-zero attributes and the flags set in changes are sent and modified, all others are left as
+// they are. If the account does not exist, ErrNotFound is returned, if the version does not match the current version
+// of the account, ErrConflict.
+func (c *Client) PatchAccount(accountId string, version uint64, changes *AccountChanges) (*Account, Err) {
+	return c.PatchAccountWithContext(context.Background(), accountId, version, changes)
+}
+
+// PatchAccountWithContext is like PatchAccount, but the request is bound to the given context. If the context is
+// canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) PatchAccountWithContext(ctx context.Context, accountId string, version uint64, changes *AccountChanges) (*Account, Err) {
+	return c.patchAccount(ctx, accountId, version, changes.attributes())
+}
+
+// patchAccount sends the given attributes to patch the account.
+func (c *Client) patchAccount(ctx context.Context, accountId string, version uint64, attributes map[string]any) (*Account, Err) {
+	ctx, end := c.begin(ctx, opPatchAccount, "account_id", accountId, "version", version)
+	patch := accountPatch{Attr: attributes}
+	patch.Id = accountId
+	patch.Type = TypeAccount
+	patch.Version = &version
+	uri := fmt.Sprintf("%s/%s", c.accountUri, url.QueryEscape(accountId))
+	var envelope AccountEnvelope
+	er := call(ctx, c, opPatchAccount, http.MethodPatch, uri, &accountPatchEnvelope{&patch}, &envelope)
+	if er == nil && envelope.Data == nil {
+		er = err{code: ErrResponse, msg: "The response does not contain the account"}
+	}
+	end(er)
+	if er != nil {
+		return nil, er
+	}
+	return envelope.Data, nil
+}
+
+// WithUpdateAttempts sets the maximal amount of attempts of UpdateAccount and DeleteAccountLatest instead of the
+// DefaultUpdateAttempts.
+func WithUpdateAttempts(attempts int) Option {
+	return func(c *Client) {
+		c.updateAttempts = attempts
+	}
+}
+
+// UpdateAccount fetches the account with the given id, applies the given mutation to it and patches the changed
+// attributes using the fetched version. If the account was modified concurrently, so the patch is rejected with a
+// conflict, the account is fetched again and the mutation is reapplied, until the configured amount of attempts is
+// reached. In that case ErrConflict is returned with a VersionConflict as cause. Only changes of the attributes are
+// sent, changes of the id or version are ignored. If the mutation returns an error, the update is aborted and
+// ErrGeneric is returned with the error as cause.
+func (c *Client) UpdateAccount(accountId string, mutate func(account *Account) error) (*Account, Err) {
+	return c.UpdateAccountWithContext(context.Background(), accountId, mutate)
+}
+
+// UpdateAccountWithContext is like UpdateAccount, but all requests are bound to the given context. If the context is
+// canceled or its deadline exceeded before the update finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) UpdateAccountWithContext(ctx context.Context, accountId string, mutate func(account *Account) error) (*Account, Err) {
+	var lastConflict Err
+	for attempt := 1; attempt <= c.updateAttempts || attempt == 1; attempt++ {
+		account, er := c.FetchAccountWithContext(ctx, accountId)
+		if er != nil {
+			return nil, er
+		}
+		if account.Version == nil {
+			return nil, err{code: ErrResponse, msg: "The fetched account has no version"}
+		}
+		version := *account.Version
+		var before, after map[string]any
+		if !toJsonMap(account.Attr, &before) {
+			return nil, err{code: ErrGeneric, msg: "Failed to read the attributes of the account"}
+		}
+		if e := mutate(account); e != nil {
+			return nil, err{code: ErrGeneric, msg: "Update aborted by the mutation", cause: e}
+		}
+		if !toJsonMap(account.Attr, &after) {
+			return nil, err{code: ErrGeneric, msg: "Failed to read the mutated attributes of the account"}
+		}
+		changes := diffAttributes(before, after)
+		if len(changes) == 0 {
+			account.Version = &version
+			return account, nil
+		}
+		patched, er := c.patchAccount(ctx, accountId, version, changes)
+		if er == nil {
+			return patched, nil
+		}
+		if er.ErrorCode() != ErrConflict {
+			return nil, er
+		}
+		c.log(LogInfo, "Update conflict, retry", "account_id", accountId, "version", version, "attempt", attempt)
+		conflict := &VersionConflict{AccountId: accountId, Version: version, Attempts: attempt}
+		lastConflict = err{code: ErrConflict, msg: conflict.Error(), cause: conflict, req: er.Request(), resp: er.Response()}
+	}
+	return nil, lastConflict
+}
+
+// diffAttributes returns the attributes that differ between before and after. Attributes removed by the mutation are
+// set to nil, so that they are cleared, flags are set to false.
+func diffAttributes(before map[string]any, after map[string]any) map[string]any {
+	changes := map[string]any{}
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changes[key] = value
+		}
+	}
+	for key, value := range before {
+		if _, exists := after[key]; !exists {
+			if _, isFlag := value.(bool); isFlag {
+				changes[key] = false
+			} else {
+				changes[key] = nil
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,188 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newPatchServer returns a test server holding the given account, that applies patches with optimistic locking.
+func newPatchServer(t *testing.T, account *f3.Account) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+			return
+		}
+		if r.Method != http.MethodPatch {
+			t.Errorf("Unexpected method %s", r.Method)
+			return
+		}
+		var patch struct {
+			Data struct {
+				Version    uint64         `json:"version"`
+				Attributes map[string]any `json:"attributes"`
+			} `json:"data"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&patch)
+		if patch.Data.Version != *account.Version {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error_message":"invalid version"}`))
+			return
+		}
+		raw, _ := json.Marshal(account.Attr)
+		var attributes map[string]any
+		_ = json.Unmarshal(raw, &attributes)
+		for key, value := range patch.Data.Attributes {
+			attributes[key] = value
+		}
+		raw, _ = json.Marshal(attributes)
+		account.Attr = new(f3.AccountAttr)
+		_ = json.Unmarshal(raw, account.Attr)
+		version := *account.Version + 1
+		account.Version = &version
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+}
+
+func TestClient_PatchAccount(t *testing.T) {
+	account := createTestAccount(true)
+	version := uint64(0)
+	account.Version = &version
+	server := newPatchServer(t, account)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	changes := &f3.AccountChanges{Attr: &f3.AccountAttr{AccountClassification: "Business"}}
+	patched, e := client.PatchAccount(account.Id, 0, changes)
+	if e != nil {
+		t.Fatalf("Failed to patch the account: %s", e.Error())
+	}
+	if patched.Version == nil || *patched.Version != 1 {
+		t.Errorf("Expected the version 1, got %v", patched.Version)
+	}
+	if patched.Attr.AccountClassification != "Business" || patched.Attr.Country != "GB" {
+		t.Errorf("Expected only the classification to be changed, got: %+v", patched.Attr)
+	}
+
+	changes = &f3.AccountChanges{Attr: &f3.AccountAttr{AccountClassification: "Personal"}}
+	_, e = client.PatchAccount(account.Id, 0, changes)
+	if e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for the outdated version, got: %v", e)
+	}
+}
+
+func TestClient_PatchAccountFlags(t *testing.T) {
+	account := createTestAccount(true)
+	version := uint64(0)
+	account.Version = &version
+	account.Attr.Switched = true
+	account.Attr.JointAccount = true
+	account.Attr.AccountMatchingOptOut = true
+	server := newPatchServer(t, account)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	off := false
+	patched, e := client.PatchAccount(account.Id, 0, &f3.AccountChanges{JointAccount: &off})
+	if e != nil {
+		t.Fatalf("Failed to patch the account: %s", e.Error())
+	}
+	if patched.Attr.JointAccount || !patched.Attr.Switched || !patched.Attr.AccountMatchingOptOut {
+		t.Errorf("Expected only the joint account flag to be cleared, got: %+v", patched.Attr)
+	}
+
+	updated, e := client.UpdateAccount(account.Id, func(account *f3.Account) error {
+		account.Attr.Switched = false
+		return nil
+	})
+	if e != nil {
+		t.Fatalf("Failed to update the account: %s", e.Error())
+	}
+	if updated.Attr.Switched || !updated.Attr.AccountMatchingOptOut {
+		t.Errorf("Expected only the switched flag to be cleared, got: %+v", updated.Attr)
+	}
+}
+
+func TestClient_UpdateAccount(t *testing.T) {
+	account := createTestAccount(true)
+	version := uint64(3)
+	account.Version = &version
+	server := newPatchServer(t, account)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithUpdateAttempts(3))
+
+	mutations := 0
+	updated, e := client.UpdateAccount(account.Id, func(fetched *f3.Account) error {
+		mutations++
+		if mutations == 1 {
+			// Simulate a concurrent modification.
+			concurrent := *account.Version + 1
+			account.Version = &concurrent
+		}
+		fetched.Attr.WithStatusClosed("Customer request")
+		return nil
+	})
+	if e != nil {
+		t.Fatalf("Failed to update the account: %s", e.Error())
+	}
+	if mutations != 2 {
+		t.Errorf("Expected the mutation to be applied twice, but was applied %d times", mutations)
+	}
+	if updated.Attr.Status != f3.StatusClosed || updated.Version == nil || *updated.Version != 5 {
+		t.Errorf("Unexpected updated account: %+v, version %v", updated.Attr, updated.Version)
+	}
+
+	_, e = client.UpdateAccount(account.Id, func(fetched *f3.Account) error {
+		concurrent := *account.Version + 1
+		account.Version = &concurrent
+		fetched.Attr.Country = "DE"
+		return nil
+	})
+	if e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Fatalf("Expected a conflict, got: %v", e)
+	}
+	var conflict *f3.VersionConflict
+	if !errors.As(e.Unwrap(), &conflict) {
+		t.Fatalf("Expected a version conflict as cause, got: %v", e.Unwrap())
+	}
+	if conflict.Attempts != 3 || conflict.Version != 7 {
+		t.Errorf("Expected 3 attempts and the last seen version 7, got: %+v", conflict)
+	}
+}
+
+func TestClient_UpdateAccountPreservesUnknownAttributes(t *testing.T) {
+	account := createTestAccount(true)
+	version := uint64(0)
+	account.Version = &version
+	account.Attr.Unknown = map[string]json.RawMessage{"future_field": json.RawMessage(`{"enabled":true}`)}
+	server := newPatchServer(t, account)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	updated, e := client.UpdateAccount(account.Id, func(account *f3.Account) error {
+		if string(account.Attr.Unknown["future_field"]) != `{"enabled":true}` {
+			t.Errorf("Expected the unknown attribute to be fetched, but got: %v", account.Attr.Unknown)
+		}
+		account.Attr.Name = []string{"Jane Smith"}
+		return nil
+	})
+	if e != nil {
+		t.Fatalf("Failed to update the account: %s", e.Error())
+	}
+	fetched, e := client.FetchAccount(account.Id)
+	if e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	patched, e := client.PatchAccount(account.Id, *fetched.Version, &f3.AccountChanges{Attr: fetched.Attr})
+	if e != nil {
+		t.Fatalf("Failed to patch the account: %s", e.Error())
+	}
+	for _, attr := range []*f3.AccountAttr{updated.Attr, patched.Attr} {
+		if attr.Name[0] != "Jane Smith" || string(attr.Unknown["future_field"]) != `{"enabled":true}` {
+			t.Errorf("Expected the unknown attribute to be preserved, but got: %+v", attr)
+		}
+	}
+}
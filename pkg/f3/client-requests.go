@@ -0,0 +1,254 @@
+package f3
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultSubmissionPollInterval is the default interval in which AwaitAccountRequestSubmission polls a submission.
+var DefaultSubmissionPollInterval = time.Second
+
+// AccountRequestFilter filters the account requests to list. Every slice holds the values to accept, a request must
+// match at least one value of every non-empty field.
+type AccountRequestFilter struct {
+	OrganisationId []string
+	Bic            []string
+	BankId         []string
+	Iban           []string
+	AccountNumber  []string
+	Country        []string
+
+	// SubmissionStatus accepts only requests with a submission in the given status.
+	SubmissionStatus SubmissionStatusString
+
+	// SubmittedFrom and SubmittedTo accept only requests submitted in the given period.
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+}
+
+// encode adds the filter to the given query.
+func (f *AccountRequestFilter) encode(query url.Values) {
+	if f == nil {
+		return
+	}
+	for name, values := range map[string][]string{
+		"organisation_id": f.OrganisationId,
+		"bic":             f.Bic,
+		"bank_id":         f.BankId,
+		"iban":            f.Iban,
+		"account_number":  f.AccountNumber,
+		"country":         f.Country,
+	} {
+		if len(values) > 0 {
+			query.Set(fmt.Sprintf("filter[%s]", name), strings.Join(values, ","))
+		}
+	}
+	encodeSubmissionFilter(query, f.SubmissionStatus, f.SubmittedFrom, f.SubmittedTo)
+}
+
+// encodeSubmissionFilter adds the filter on the submissions of a resource to the given query.
+func encodeSubmissionFilter(query url.Values, status SubmissionStatusString, from *time.Time, to *time.Time) {
+	if len(status) > 0 {
+		query.Set("filter[submission.status]", string(status))
+	}
+	if from != nil {
+		query.Set("filter[submission.submission_date_from]", from.UTC().Format(time.RFC3339))
+	}
+	if to != nil {
+		query.Set("filter[submission.submission_date_to]", to.UTC().Format(time.RFC3339))
+	}
+}
+
+// ListAccountRequests returns a single page of the account requests matching the given filter. If no filter is
+// given, all requests are listed, if no page is given, the first page with the default size of the account API is
+// returned.
+func (c *Client) ListAccountRequests(filter *AccountRequestFilter, page *Page) (*AccountRequestsEnvelope, Err) {
+	return c.ListAccountRequestsWithContext(context.Background(), filter, page)
+}
+
+// ListAccountRequestsWithContext is like ListAccountRequests, but the request is bound to the given context. If the
+// context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) ListAccountRequestsWithContext(ctx context.Context, filter *AccountRequestFilter, page *Page) (*AccountRequestsEnvelope, Err) {
+	return c.listAccountRequests(ctx, c.listRequestsUri(filter, page))
+}
+
+// listRequestsUri returns the uri of the given page of the account requests matching the filter.
+func (c *Client) listRequestsUri(filter *AccountRequestFilter, page *Page) string {
+	uri := c.requestUri
+	query := url.Values{}
+	filter.encode(query)
+	page.encode(query)
+	if len(query) > 0 {
+		uri = fmt.Sprintf("%s?%s", uri, query.Encode())
+	}
+	return uri
+}
+
+// listAccountRequests lists the account requests from the given uri.
+func (c *Client) listAccountRequests(ctx context.Context, uri string) (*AccountRequestsEnvelope, Err) {
+	ctx, end := c.begin(ctx, opListAccountRequests)
+	var envelope AccountRequestsEnvelope
+	er := call(ctx, c, opListAccountRequests, http.MethodGet, uri, (*any)(nil), &envelope)
+	end(er)
+	if er != nil {
+		return nil, er
+	}
+	return &envelope, nil
+}
+
+// AccountRequestIterator iterates over all account requests of a list.
+type AccountRequestIterator struct {
+	Iterator[*AccountRequest]
+}
+
+// IterateAccountRequests returns an iterator over all account requests matching the given filter, fetching pages of
+// the given size.
+func (c *Client) IterateAccountRequests(ctx context.Context, filter *AccountRequestFilter, pageSize int) *AccountRequestIterator {
+	uri := c.listRequestsUri(filter, &Page{Size: pageSize})
+	return &AccountRequestIterator{newIterator(ctx, c, uri, func(ctx context.Context, uri string) ([]*AccountRequest, *Links, Err) {
+		envelope, er := c.listAccountRequests(ctx, uri)
+		if er != nil {
+			return nil, nil, er
+		}
+		return envelope.Data, envelope.Links, nil
+	})}
+}
+
+// Request returns the current account request.
+func (it *AccountRequestIterator) Request() *AccountRequest {
+	return it.Value()
+}
+
+// CreateAccountRequest creates the given account request and returns it as returned from the server. If the request
+// has no organisation identifier, the one of the client is used. The account is not opened before the request is
+// submitted, see CreateAccountRequestSubmission.
+func (c *Client) CreateAccountRequest(request *AccountRequest) (*AccountRequest, Err) {
+	return c.CreateAccountRequestWithContext(context.Background(), request)
+}
+
+// CreateAccountRequestWithContext is like CreateAccountRequest, but the request is bound to the given context. If the
+// context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) CreateAccountRequestWithContext(ctx context.Context, request *AccountRequest) (*AccountRequest, Err) {
+	ctx, end := c.begin(ctx, opCreateAccountRequest)
+	var er Err
+	var envelope AccountRequestEnvelope
+	if request == nil {
+		er = err{code: ErrRequest, msg: "No account request given"}
+	} else {
+		if len(request.OrganisationId) == 0 && len(c.organisationId) > 0 {
+			withOrganisation := *request
+			withOrganisation.OrganisationId = c.organisationId
+			request = &withOrganisation
+		}
+		er = call(ctx, c, opCreateAccountRequest, http.MethodPost, c.requestUri, &AccountRequestEnvelope{request}, &envelope)
+	}
+	created, er := present(envelope.Data, er)
+	end(er)
+	return created, er
+}
+
+// FetchAccountRequest returns the account request with the given id or ErrNotFound, if it does not exist.
+func (c *Client) FetchAccountRequest(requestId string) (*AccountRequest, Err) {
+	return c.FetchAccountRequestWithContext(context.Background(), requestId)
+}
+
+// FetchAccountRequestWithContext is like FetchAccountRequest, but the request is bound to the given context. If the
+// context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is
+// returned.
+func (c *Client) FetchAccountRequestWithContext(ctx context.Context, requestId string) (*AccountRequest, Err) {
+	ctx, end := c.begin(ctx, opFetchAccountRequest, "account_request_id", requestId)
+	var envelope AccountRequestEnvelope
+	uri := fmt.Sprintf("%s/%s", c.requestUri, url.QueryEscape(requestId))
+	er := call(ctx, c, opFetchAccountRequest, http.MethodGet, uri, (*any)(nil), &envelope)
+	fetched, er := present(envelope.Data, er)
+	end(er)
+	return fetched, er
+}
+
+// submissionsUri returns the uri of the submissions of the given account request.
+func (c *Client) submissionsUri(requestId string) string {
+	return fmt.Sprintf("%s/%s/submissions", c.requestUri, url.QueryEscape(requestId))
+}
+
+// CreateAccountRequestSubmission submits the account request with the given id and returns the new submission. The
+// account is opened asynchronously, use FetchAccountRequestSubmission or AwaitAccountRequestSubmission to follow the
+// status of the submission.
+func (c *Client) CreateAccountRequestSubmission(requestId string) (*AccountRequestSubmission, Err) {
+	return c.CreateAccountRequestSubmissionWithContext(context.Background(), requestId)
+}
+
+// CreateAccountRequestSubmissionWithContext is like CreateAccountRequestSubmission, but the request is bound to the
+// given context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled
+// respectively ErrTimeout is returned.
+func (c *Client) CreateAccountRequestSubmissionWithContext(ctx context.Context, requestId string) (*AccountRequestSubmission, Err) {
+	ctx, end := c.begin(ctx, opCreateAccountRequestSubmission, "account_request_id", requestId)
+	submission := AccountRequestSubmission{}
+	submission.Id = uuid.New().String()
+	submission.Type = TypeAccountRequestSubmission
+	submission.OrganisationId = c.organisationId
+	var envelope AccountRequestSubmissionEnvelope
+	er := call(ctx, c, opCreateAccountRequestSubmission, http.MethodPost, c.submissionsUri(requestId), &AccountRequestSubmissionEnvelope{&submission}, &envelope)
+	created, er := present(envelope.Data, er)
+	end(er)
+	return created, er
+}
+
+// FetchAccountRequestSubmission returns the submission with the given id of the account request with the given id
+// or ErrNotFound, if it does not exist.
+func (c *Client) FetchAccountRequestSubmission(requestId string, submissionId string) (*AccountRequestSubmission, Err) {
+	return c.FetchAccountRequestSubmissionWithContext(context.Background(), requestId, submissionId)
+}
+
+// FetchAccountRequestSubmissionWithContext is like FetchAccountRequestSubmission, but the request is bound to the given
+// context. If the context is canceled or its deadline exceeded before the request finished, ErrCanceled respectively
+// ErrTimeout is returned.
+func (c *Client) FetchAccountRequestSubmissionWithContext(ctx context.Context, requestId string, submissionId string) (*AccountRequestSubmission, Err) {
+	ctx, end := c.begin(ctx, opFetchAccountRequestSubmission, "account_request_id", requestId, "submission_id", submissionId)
+	var envelope AccountRequestSubmissionEnvelope
+	uri := fmt.Sprintf("%s/%s", c.submissionsUri(requestId), url.QueryEscape(submissionId))
+	er := call(ctx, c, opFetchAccountRequestSubmission, http.MethodGet, uri, (*any)(nil), &envelope)
+	fetched, er := present(envelope.Data, er)
+	end(er)
+	return fetched, er
+}
+
+// AwaitAccountRequestSubmission polls the submission with the given id of the account request with the given id in
+// the given interval, until the submission reaches a terminal status, and returns the opened account. A zero interval
+// selects the DefaultSubmissionPollInterval. If the delivery failed, ErrBadRequest with a SubmissionFailed cause is
+// returned, if the context is done before, ErrCanceled or ErrTimeout.
+func (c *Client) AwaitAccountRequestSubmission(ctx context.Context, requestId string, submissionId string, interval time.Duration) (*Account, Err) {
+	if interval <= 0 {
+		interval = DefaultSubmissionPollInterval
+	}
+	for {
+		submission, er := c.FetchAccountRequestSubmissionWithContext(ctx, requestId, submissionId)
+		if er != nil {
+			return nil, er
+		}
+		if submission.Attr != nil && submission.Attr.Status.Terminal() {
+			return c.submittedAccount(ctx, submission)
+		}
+		if sleep(ctx, interval) != nil {
+			return nil, contextError(ctx, "waiting for the submission", nil, nil)
+		}
+	}
+}
+
+// submittedAccount returns the account opened by the given terminal submission.
+func (c *Client) submittedAccount(ctx context.Context, submission *AccountRequestSubmission) (*Account, Err) {
+	if submission.Attr.Status != SubmissionDeliveryConfirmed {
+		failed := &SubmissionFailed{SubmissionId: submission.Id, Status: submission.Attr.Status, Reason: submission.Attr.StatusReason}
+		return nil, err{code: ErrBadRequest, msg: failed.Error(), cause: failed}
+	}
+	if submission.Relationships == nil || submission.Relationships.Account == nil || len(submission.Relationships.Account.Data) == 0 {
+		return nil, err{code: ErrResponse, msg: fmt.Sprintf("Submission %s does not refer to an account", submission.Id)}
+	}
+	return c.FetchAccountWithContext(ctx, submission.Relationships.Account.Data[0].Id)
+}
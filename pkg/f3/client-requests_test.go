@@ -0,0 +1,154 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newRequestServer returns a test server for account requests, which lets every submission pass through the given
+// states, one per fetch, and opens the given account, if the last state is f3.SubmissionDeliveryConfirmed.
+func newRequestServer(t *testing.T, account *f3.Account, states ...f3.SubmissionStatusString) *httptest.Server {
+	var mutex sync.Mutex
+	requests := map[string]*f3.AccountRequest{}
+	fetches := map[string]int{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/organisation/"), "/")
+		switch {
+		case path[0] == "accounts" && len(path) == 2 && path[1] == account.Id:
+			_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+		case path[0] != "accountrequests":
+			w.WriteHeader(http.StatusNotFound)
+		case len(path) == 1 && r.Method == http.MethodPost:
+			var envelope f3.AccountRequestEnvelope
+			_ = json.NewDecoder(r.Body).Decode(&envelope)
+			if requests[envelope.Data.Id] != nil {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			requests[envelope.Data.Id] = envelope.Data
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case len(path) == 1:
+			if r.URL.Query().Get("filter[country]") != "GB" {
+				t.Errorf("Expected the country filter GB, but got: %s", r.URL.RawQuery)
+			}
+			envelope := f3.AccountRequestsEnvelope{Data: []*f3.AccountRequest{}}
+			for _, request := range requests {
+				envelope.Data = append(envelope.Data, request)
+			}
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case requests[path[1]] == nil:
+			w.WriteHeader(http.StatusNotFound)
+		case len(path) == 2:
+			_ = json.NewEncoder(w).Encode(&f3.AccountRequestEnvelope{Data: requests[path[1]]})
+		case len(path) == 3 && r.Method == http.MethodPost:
+			var envelope f3.AccountRequestSubmissionEnvelope
+			_ = json.NewDecoder(r.Body).Decode(&envelope)
+			if envelope.Data.Type != f3.TypeAccountRequestSubmission || envelope.Data.Id == "" {
+				t.Errorf("Submitted an invalid submission: %v", envelope.Data)
+			}
+			envelope.Data.Attr = &f3.AccountRequestSubmissionAttr{Status: f3.SubmissionAccepted}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&envelope)
+		case len(path) == 4:
+			state := states[fetches[path[3]]]
+			if fetches[path[3]] < len(states)-1 {
+				fetches[path[3]]++
+			}
+			submission := &f3.AccountRequestSubmission{Attr: &f3.AccountRequestSubmissionAttr{Status: state}}
+			submission.Id = path[3]
+			if state == f3.SubmissionDeliveryConfirmed {
+				submission.Relationships = &f3.AccountRequestSubmissionRelationships{
+					Account: &f3.Relationship{Data: []f3.RelationshipData{{Id: account.Id, Type: f3.TypeAccount}}},
+				}
+			}
+			if state == f3.SubmissionDeliveryFailed {
+				submission.Attr.StatusReason = "Invalid BIC"
+			}
+			_ = json.NewEncoder(w).Encode(&f3.AccountRequestSubmissionEnvelope{Data: submission})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_AccountRequestSubmission(t *testing.T) {
+	account := createTestAccount(true)
+	server := newRequestServer(t, account, f3.SubmissionSubmitted, f3.SubmissionDeliveryConfirmed)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+	request, e := client.CreateAccountRequest(f3.NewAccountRequest(nil, "GB", "NWBKGB22", "GBP"))
+	if e != nil {
+		t.Fatalf("Failed to create the account request: %s", e.Error())
+	}
+	if _, e = client.CreateAccountRequest(request); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for a duplicate request, but got: %v", e)
+	}
+	fetched, e := client.FetchAccountRequest(request.Id)
+	if e != nil || fetched.Attr.Bic != "NWBKGB22" {
+		t.Fatalf("Failed to fetch the account request: %v", e)
+	}
+	envelope, e := client.ListAccountRequests(&f3.AccountRequestFilter{Country: []string{"GB"}}, nil)
+	if e != nil || len(envelope.Data) != 1 {
+		t.Fatalf("Failed to list the account requests: %v", e)
+	}
+
+	submission, e := client.CreateAccountRequestSubmission(request.Id)
+	if e != nil {
+		t.Fatalf("Failed to submit the account request: %s", e.Error())
+	}
+	if submission.Attr.Status.Terminal() {
+		t.Errorf("Expected a new submission to be pending, but got: %s", submission.Attr.Status)
+	}
+	opened, e := client.AwaitAccountRequestSubmission(context.Background(), request.Id, submission.Id, time.Millisecond)
+	if e != nil {
+		t.Fatalf("Failed to await the submission: %s", e.Error())
+	}
+	if opened.Id != account.Id {
+		t.Errorf("Expected the account %s, but got: %s", account.Id, opened.Id)
+	}
+}
+
+func TestClient_AwaitAccountRequestSubmissionFailed(t *testing.T) {
+	server := newRequestServer(t, createTestAccount(true), f3.SubmissionDeliveryFailed)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+	request, e := client.CreateAccountRequest(f3.NewAccountRequest(nil, "GB", "INVALID", "GBP"))
+	if e != nil {
+		t.Fatalf("Failed to create the account request: %s", e.Error())
+	}
+	_, e = client.AwaitAccountRequestSubmission(context.Background(), request.Id, "submission", time.Millisecond)
+	var failed *f3.SubmissionFailed
+	if e == nil || e.ErrorCode() != f3.ErrBadRequest || !errors.As(e, &failed) || failed.Reason != "Invalid BIC" {
+		t.Fatalf("Expected a failed submission, but got: %v", e)
+	}
+}
+
+func TestClient_AwaitAccountRequestSubmissionTimeout(t *testing.T) {
+	server := newRequestServer(t, createTestAccount(true), f3.SubmissionValidationPending)
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL + "/v1"))
+
+	request, e := client.CreateAccountRequest(f3.NewAccountRequest(nil, "GB", "NWBKGB22", "GBP"))
+	if e != nil {
+		t.Fatalf("Failed to create the account request: %s", e.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, e = client.AwaitAccountRequestSubmission(ctx, request.Id, "submission", 5*time.Millisecond)
+	if e == nil || e.ErrorCode() != f3.ErrTimeout {
+		t.Fatalf("Expected the wait to time out, but got: %v", e)
+	}
+}
@@ -2,12 +2,16 @@ package f3
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"reflect"
+	"time"
 )
 
 const (
@@ -20,12 +24,23 @@ const (
 	mimeForm3Json       = "application/vnd.api+json"
 )
 
-// NewClient creates a new Form3 client bound to the production endpoint and setup with defaults.
-func NewClient() *Client {
-	client := Client{endpoint: DefaultEndPoint, httpClient: http.Client{Timeout: DefaultTimeout, Transport: DefaultTransport}}
-	client.healthCheckUri = fmt.Sprintf("%s/health", client.endpoint)
-	client.accountUri = fmt.Sprintf("%s/organisation/accounts", client.endpoint)
-	return &client
+// NewClient creates a new Form3 client bound to the production endpoint and setup with defaults. The defaults can be
+// changed by the given options, which are applied in order. Requests are not retried, unless a retry policy is set
+// with WithRetryPolicy.
+func NewClient(opts ...Option) *Client {
+	client := Client{
+		endpoint:       DefaultEndPoint,
+		userAgent:      userAgentName,
+		organisationId: DefaultOrganizationId,
+		retryPolicy:    NoRetry,
+		updateAttempts: DefaultUpdateAttempts,
+		redactedFields: toSet(DefaultRedactedFields),
+		httpClient:     &http.Client{Timeout: DefaultTimeout, Transport: DefaultTransport},
+	}
+	for _, opt := range opts {
+		opt(&client)
+	}
+	return client.WithEndPoint(client.endpoint)
 }
 
 // Client is an abstraction above a http.Client bound to a specific Form3 endpoint.
@@ -33,7 +48,23 @@ type Client struct {
 	endpoint       string
 	healthCheckUri string
 	accountUri     string
-	httpClient     http.Client
+	requestUri     string
+	amendmentUri   string
+	userAgent      string
+	organisationId string
+	logger         Logger
+	retryPolicy    RetryPolicy
+	breaker        *circuitBreaker
+	limiter        *limiter
+	middlewares    []Middleware
+	logPayloads    bool
+	redactedFields map[string]bool
+	metrics        *Metrics
+	tracer         Tracer
+	updateAttempts int
+	validator      SchemaValidator
+	validationMode ValidationMode
+	httpClient     *http.Client
 }
 
 // WithEndPoint rebinds the endpoint of the client.
@@ -41,42 +72,94 @@ func (c *Client) WithEndPoint(endpoint string) *Client {
 	c.endpoint = endpoint
 	c.healthCheckUri = fmt.Sprintf("%s/health", endpoint)
 	c.accountUri = fmt.Sprintf("%s/organisation/accounts", endpoint)
+	c.requestUri = fmt.Sprintf("%s/organisation/accountrequests", endpoint)
+	c.amendmentUri = fmt.Sprintf("%s/organisation/accountamendments", endpoint)
 	return c
 }
 
 // HttpClient returns the underlying http client being used. If the default created by NewClient is not sufficient,
-// modify this before using.
-func (c *Client) HttpClient() http.Client {
+// modify this before using or use the options WithTimeout, WithTransport or WithHttpClient.
+func (c *Client) HttpClient() *http.Client {
 	return c.httpClient
 }
 
+// do sends the given attempt of the request with the user agent of the client through the middlewares using the
+// underlying http client and logs the outcome.
+func (c *Client) do(req *http.Request, attempt int) (*http.Response, error) {
+	req.Header.Set(headerUserAgent, c.userAgent)
+	if c.metrics != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), c.metrics.trace()))
+		defer c.metrics.begin()()
+	}
+	if value := traceParent(req.Context()); len(value) > 0 {
+		req.Header.Set(headerTraceParent, value)
+	}
+	span := spanFromContext(req.Context())
+	if span != nil {
+		span.SetAttribute("f3.attempts", attempt)
+	}
+	start := time.Now()
+	resp, e := c.chain()(req)
+	if e == nil && resp == nil {
+		e = errNoResponse
+	}
+	if span != nil && e == nil {
+		span.SetAttribute("http.status_code", resp.StatusCode)
+	}
+	c.logExchange(req, resp, e, attempt, time.Since(start))
+	return resp, e
+}
+
 // IsHealthy tests if the service is alive and responsive within the set request timeout.
 func (c *Client) IsHealthy() bool {
+	return c.IsHealthyWithContext(context.Background())
+}
+
+// IsHealthyWithContext tests if the service is alive and responsive within the set request timeout or until the given
+// context is done, whatever happens first.
+func (c *Client) IsHealthyWithContext(ctx context.Context) bool {
+	ctx, end := c.begin(ctx, opHealth)
+	er := c.checkHealth(ctx)
+	end(er)
+	return er == nil
+}
+
+// checkHealth sends the health check and returns nil, if the service reports to be up.
+func (c *Client) checkHealth(ctx context.Context) Err {
 	var (
 		req  *http.Request
 		resp *http.Response
 		e    error
 		raw  []byte
 	)
-	if req, e = http.NewRequest(http.MethodGet, c.healthCheckUri, nil); e == nil {
-		req.Header.Set(headerUserAgent, userAgentName)
-		req.Header.Set(headerAccept, mimeApplicationJson)
-		if resp, e = c.httpClient.Do(req); e == nil && resp.Body != nil {
-			//goland:noinspection GoUnhandledErrorResult
-			defer resp.Body.Close()
-			if raw, e = ioutil.ReadAll(resp.Body); e == nil {
-				response := HealthyResponse{}
-				if e = json.Unmarshal(raw, &response); e == nil {
-					return response.Status != nil && *response.Status == "up"
-				}
+	if req, e = http.NewRequestWithContext(ctx, http.MethodGet, c.healthCheckUri, nil); e != nil {
+		return err{code: ErrGeneric, msg: "Unknown error while creating the request", cause: e}
+	}
+	req.Header.Set(headerAccept, mimeApplicationJson)
+	if resp, _, e = c.send(opHealth, req); e != nil || resp == nil {
+		return requestFailed(ctx, e, req, resp)
+	}
+	if raw, e = readBody(resp); e == nil {
+		// Violations are only logged, the status decides about the health.
+		_ = c.validate(opHealth, opHealth.response, "response", raw)
+		response := HealthyResponse{}
+		if e = json.Unmarshal(raw, &response); e == nil {
+			if response.Status != nil && *response.Status == "up" {
+				return nil
 			}
+			return err{code: ErrResponse, msg: "Service is not healthy", req: req, resp: resp}
 		}
 	}
-	return false
+	if ctx.Err() != nil {
+		return contextError(ctx, "reading the response", req, resp)
+	}
+	return err{code: ErrResponse, msg: "Invalid health check response", cause: e, req: req, resp: resp}
 }
 
-// createRequest creates a new request and returns it. If an object is given, this is JSON serialized and attached as body.
-func createRequest[T any](method string, uri string, object *T) (*http.Request, Err) {
+// createRequest creates a new request for the given operation bound to the given context and returns it. If an object
+// is given, this is JSON serialized and attached as body. If the client validates the schema, a body violating the
+// schema results in ErrBadRequest with ValidationStrict.
+func createRequest[T any](ctx context.Context, c *Client, op operation, method string, uri string, object *T) (*http.Request, Err) {
 	var (
 		req *http.Request
 		e   error
@@ -84,14 +167,17 @@ func createRequest[T any](method string, uri string, object *T) (*http.Request,
 	if object != nil {
 		var jsonBytes []byte
 		jsonBytes, e = json.Marshal(object)
+		if violations := c.validate(op, op.request, "request", jsonBytes); violations != nil {
+			msg := "The request violates the schema of the account API"
+			return nil, err{code: ErrBadRequest, msg: msg, cause: violations}
+		}
 		if e == nil {
-			req, e = http.NewRequest(method, uri, bytes.NewBuffer(jsonBytes))
+			req, e = http.NewRequestWithContext(ctx, method, uri, bytes.NewBuffer(jsonBytes))
 		}
 	} else {
-		req, e = http.NewRequest(method, uri, nil)
+		req, e = http.NewRequestWithContext(ctx, method, uri, nil)
 	}
 	if e == nil && req != nil {
-		req.Header.Set(headerUserAgent, userAgentName)
 		req.Header.Set(headerContentType, mimeForm3Json)
 		req.Header.Set(headerAccept, mimeForm3Json)
 		return req, nil
@@ -99,38 +185,144 @@ func createRequest[T any](method string, uri string, object *T) (*http.Request,
 	return nil, err{code: ErrGeneric, msg: "Unknown error while creating the request", cause: e, req: req}
 }
 
-// parseResponse parses the JSON of the given response into the given object. If no object is given, no response is expected.
-func parseResponse[T any](req *http.Request, resp *http.Response, object *T) Err {
-	var (
-		e    error
-		body []byte
-	)
-	//goland:noinspection GoUnhandledErrorResult
-	defer resp.Body.Close()
-
-	body, e = ioutil.ReadAll(resp.Body)
-	if e == nil {
-		e = json.Unmarshal(body, object)
+// parseResponse parses the JSON of the given response of the given operation into the given object and closes the body
+// of the response. If no object is given, no response is expected.
+// If reading the body fails, because the context of the request is done, ErrCanceled or ErrTimeout is returned. Every
+// 2xx status is treated as success, error statuses are mapped to their codes with an ApiError as cause. A successful
+// response, that can't be read or parsed, results in ErrResponse, as well as a response violating the schema with
+// ValidationStrict.
+func parseResponse[T any](ctx context.Context, c *Client, op operation, req *http.Request, resp *http.Response, object *T) Err {
+	body, e := readBody(resp)
+	if e != nil && ctx.Err() != nil {
+		return contextError(ctx, "reading the response", req, resp)
 	}
-	if resp.StatusCode == 200 || resp.StatusCode == 201 {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if e == errBodyTooLarge {
+			return err{code: ErrResponse, msg: "The response is too large", cause: e, req: req, resp: resp}
+		}
+		if e != nil {
+			return err{code: ErrResponse, msg: "Failed to read the response", cause: e, req: req, resp: resp}
+		}
+		if object != nil && len(body) > 0 {
+			if violations := c.validate(op, op.response, "response", body); violations != nil {
+				msg := "The response violates the schema of the account API"
+				return err{code: ErrResponse, msg: msg, cause: violations, req: req, resp: resp}
+			}
+			if e = json.Unmarshal(body, object); e != nil {
+				msg := fmt.Sprintf("Invalid response of type %q", resp.Header.Get(headerContentType))
+				return err{code: ErrResponse, msg: msg, cause: e, req: req, resp: resp}
+			}
+		}
 		return nil
 	}
-	if body != nil {
-		var errResponse *ErrorResponse
-		e = json.Unmarshal(body, &errResponse)
-		if errResponse != nil {
-			e = errors.New(errResponse.ErrorMessage)
-		}
+	if e == nil && c.validator != nil && json.Valid(body) {
+		// Violations of error responses are only logged, the status is more important.
+		_ = c.validate(op, definitionApiError, "response", body)
 	}
-	if resp.StatusCode == 404 {
-		return err{code: ErrNotFound, msg: "Not found", cause: e, req: req, resp: resp}
+	code, msg := statusError(resp)
+	return err{code: code, msg: msg, cause: newApiError(resp.StatusCode, body), req: req, resp: resp}
+}
+
+// statusError returns the code and message of the error for the status of the given response.
+func statusError(resp *http.Response) (int, string) {
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized, "Unauthorized"
+	case resp.StatusCode == http.StatusForbidden:
+		return ErrForbidden, "Forbidden"
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound, "Not found"
+	case resp.StatusCode == http.StatusConflict:
+		return ErrConflict, "Conflict, version does not match"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited, "Rate limited"
+	case resp.StatusCode >= 500:
+		return ErrServer, fmt.Sprintf("Server error: %s", resp.Status)
+	}
+	return ErrBadRequest, "Bad Request: The given payload was invalid"
+}
+
+// contextError returns ErrTimeout, if the deadline of the given context exceeded, otherwise ErrCanceled, both with
+// the error of the context as cause. The action describes what was done, when the context was done.
+func contextError(ctx context.Context, action string, req *http.Request, resp *http.Response) Err {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err{code: ErrTimeout, msg: "Timed out while " + action, cause: ctx.Err(), req: req, resp: resp}
+	}
+	return err{code: ErrCanceled, msg: "Canceled while " + action, cause: ctx.Err(), req: req, resp: resp}
+}
+
+// requestFailed returns the error to report when sending the request failed. If the context is done, ErrCanceled or
+// ErrTimeout is returned with the context error as cause, if the HTTP client timed out ErrTimeout, if the circuit
+// breaker rejected the request ErrCircuitOpen, if the client side limits are exhausted ErrThrottled, otherwise
+// ErrRequest with the given cause.
+func requestFailed(ctx context.Context, cause error, req *http.Request, resp *http.Response) Err {
+	if ctx.Err() != nil {
+		return contextError(ctx, "sending the request", req, resp)
+	}
+	var netErr net.Error
+	if errors.As(cause, &netErr) && netErr.Timeout() {
+		return err{code: ErrTimeout, msg: "Request timed out", cause: cause, req: req, resp: resp}
+	}
+	if cause == errCircuitOpen {
+		return err{code: ErrCircuitOpen, msg: "Circuit open, request rejected", cause: cause, req: req, resp: resp}
+	}
+	if cause == errThrottled {
+		return err{code: ErrThrottled, msg: "Request throttled by the client", cause: cause, req: req, resp: resp}
 	}
-	return err{code: ErrBadRequest, msg: "Bad Request: The given payload was invalid", cause: e, req: req, resp: resp}
+	return err{code: ErrRequest, msg: "Request failed", cause: cause, req: req, resp: resp}
+}
+
+// call sends a request with the given object as body for the given operation and parses the response into the given
+// result.
+func call[T any, R any](ctx context.Context, c *Client, op operation, method string, uri string, object *T, result *R) Err {
+	req, er := createRequest(ctx, c, op, method, uri, object)
+	if er != nil {
+		return er
+	}
+	resp, _, e := c.send(op, req)
+	if e != nil || resp == nil {
+		return requestFailed(ctx, e, req, resp)
+	}
+	return parseResponse(ctx, c, op, req, resp, result)
+}
+
+// present returns the given data, if the request succeeded. If the request succeeded, but the response does not
+// contain any data, ErrResponse is returned.
+func present[T any](data *T, er Err) (*T, Err) {
+	if er != nil {
+		return nil, er
+	}
+	if data == nil {
+		return nil, err{code: ErrResponse, msg: "The response does not contain any data"}
+	}
+	return data, nil
 }
 
 // CreateAccount creates the given account and returns the new account as returned from the server or an error, when
-// the account creation failed.
+// the account creation failed. If the account has no organisation identifier, the one of the client is used.
+//
+// If the account has an id, the creation is idempotent and therefore retried according to the retry policy. When a
+// retry is rejected with a conflict, because an earlier attempt already created the account, the account is fetched
+// and returned, if it matches the given one.
 func (c *Client) CreateAccount(account *Account) (*Account, Err) {
+	return c.CreateAccountWithContext(context.Background(), account)
+}
+
+// CreateAccountWithContext is like CreateAccount, but the request is bound to the given context. If the context is
+// canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) CreateAccountWithContext(ctx context.Context, account *Account) (*Account, Err) {
+	var accountId string
+	if account != nil {
+		accountId = account.Id
+	}
+	ctx, end := c.begin(ctx, opCreateAccount, "account_id", accountId)
+	account, er := c.createAccount(ctx, account)
+	end(er)
+	return account, er
+}
+
+// createAccount implements CreateAccountWithContext.
+func (c *Client) createAccount(ctx context.Context, account *Account) (*Account, Err) {
 	var (
 		req  *http.Request
 		resp *http.Response
@@ -138,77 +330,112 @@ func (c *Client) CreateAccount(account *Account) (*Account, Err) {
 		e    error
 	)
 	if account != nil {
+		if len(account.OrganisationId) == 0 && len(c.organisationId) > 0 {
+			withOrganisation := *account
+			withOrganisation.OrganisationId = c.organisationId
+			account = &withOrganisation
+		}
 		envelope := AccountEnvelope{account}
-		req, er = createRequest(http.MethodPost, c.accountUri, &envelope)
+		op := opCreateAccount
+		op.idempotent = len(account.Id) > 0
+		req, er = createRequest(ctx, c, op, http.MethodPost, c.accountUri, &envelope)
 		if er == nil && req != nil {
-			resp, e = c.httpClient.Do(req)
+			var attempts int
+			resp, attempts, e = c.send(op, req)
+			if e == nil && resp != nil && attempts > 1 && resp.StatusCode == http.StatusConflict {
+				discard(resp)
+				return c.fetchCreatedAccount(ctx, account, req, resp)
+			}
 			if e == nil && resp != nil {
-				er = parseResponse(req, resp, &envelope)
-				if er == nil && envelope.Data != nil {
-					return envelope.Data, nil
-				}
+				var created AccountEnvelope
+				er = parseResponse(ctx, c, op, req, resp, &created)
+				return present(created.Data, er)
 			}
 		}
 	}
 	if er != nil {
 		return nil, er
 	}
-	return nil, err{code: ErrRequest, msg: "Request failed", cause: e, req: req, resp: resp}
+	return nil, requestFailed(ctx, e, req, resp)
 }
 
-// FetchAccount returns the account with the given id or ErrNotFound if the account does not exist.
-func (c *Client) FetchAccount(accountId string) (*Account, Err) {
-	var (
-		req  *http.Request
-		resp *http.Response
-		er   Err
-		e    error
-	)
-	uri := fmt.Sprintf("%s/%s", c.accountUri, url.QueryEscape(accountId))
-	req, er = createRequest(http.MethodGet, uri, (*any)(nil))
-	if er == nil && req != nil {
-		resp, e = c.httpClient.Do(req)
-		if e == nil && resp != nil {
-			var envelope AccountEnvelope
-			er = parseResponse(req, resp, &envelope)
-			if er == nil && envelope.Data != nil {
-				return envelope.Data, nil
-			}
-		}
-	}
+// fetchCreatedAccount is called, when the creation of an account is rejected with a conflict after a retry. It
+// fetches the existing account and returns it, when it matches the account to be created. Otherwise, ErrConflict is
+// returned.
+func (c *Client) fetchCreatedAccount(ctx context.Context, account *Account, req *http.Request, resp *http.Response) (*Account, Err) {
+	fetched, er := c.FetchAccountWithContext(ctx, account.Id)
 	if er != nil {
 		return nil, er
 	}
-	return nil, err{code: ErrRequest, msg: "Request failed", cause: e, req: req, resp: resp}
+	if !isSameAccount(account, fetched) {
+		return nil, err{code: ErrConflict, msg: "Conflict, a different account with the same id exists", req: req, resp: resp}
+	}
+	return fetched, nil
+}
+
+// isSameAccount tests if the fetched account matches the account that was sent to be created. All attributes that
+// were sent must be equal, attributes set by the server, like the version or generated values, are ignored.
+func isSameAccount(sent *Account, fetched *Account) bool {
+	if fetched == nil || sent.Id != fetched.Id || sent.OrganisationId != fetched.OrganisationId {
+		return false
+	}
+	var sentAttr, fetchedAttr map[string]any
+	if !toJsonMap(sent.Attr, &sentAttr) || !toJsonMap(fetched.Attr, &fetchedAttr) {
+		return false
+	}
+	for key, value := range sentAttr {
+		if !reflect.DeepEqual(value, fetchedAttr[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// toJsonMap converts the given value into a generic JSON map using a JSON round trip.
+func toJsonMap(value any, target *map[string]any) bool {
+	raw, e := json.Marshal(value)
+	return e == nil && json.Unmarshal(raw, target) == nil
+}
+
+// FetchAccount returns the account with the given id or ErrNotFound if the account does not exist.
+func (c *Client) FetchAccount(accountId string) (*Account, Err) {
+	return c.FetchAccountWithContext(context.Background(), accountId)
+}
+
+// FetchAccountWithContext is like FetchAccount, but the request is bound to the given context. If the context is
+// canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) FetchAccountWithContext(ctx context.Context, accountId string) (*Account, Err) {
+	ctx, end := c.begin(ctx, opFetchAccount, "account_id", accountId)
+	account, er := c.fetchAccount(ctx, accountId)
+	end(er)
+	return account, er
+}
+
+// fetchAccount implements FetchAccountWithContext.
+func (c *Client) fetchAccount(ctx context.Context, accountId string) (*Account, Err) {
+	uri := fmt.Sprintf("%s/%s", c.accountUri, url.QueryEscape(accountId))
+	var envelope AccountEnvelope
+	er := call(ctx, c, opFetchAccount, http.MethodGet, uri, (*any)(nil), &envelope)
+	return present(envelope.Data, er)
 }
 
 // DeleteAccount deletes the account with the given id and return nil. If the account does not exist, ErrNotFound is
 // returned.
 func (c *Client) DeleteAccount(accountId string, version uint64) Err {
-	var (
-		req  *http.Request
-		resp *http.Response
-		er   Err
-		e    error
-	)
+	return c.DeleteAccountWithContext(context.Background(), accountId, version)
+}
+
+// DeleteAccountWithContext is like DeleteAccount, but the request is bound to the given context. If the context is
+// canceled or its deadline exceeded before the request finished, ErrCanceled respectively ErrTimeout is returned.
+func (c *Client) DeleteAccountWithContext(ctx context.Context, accountId string, version uint64) Err {
+	ctx, end := c.begin(ctx, opDeleteAccount, "account_id", accountId, "version", version)
+	er := c.deleteAccount(ctx, accountId, version)
+	end(er)
+	return er
+}
+
+// deleteAccount implements DeleteAccountWithContext.
+func (c *Client) deleteAccount(ctx context.Context, accountId string, version uint64) Err {
 	uri := fmt.Sprintf("%s/%s?version=%d", c.accountUri, url.QueryEscape(accountId), version)
-	req, er = createRequest(http.MethodDelete, uri, (*any)(nil))
-	if er == nil && req != nil {
-		resp, e = c.httpClient.Do(req)
-		if e == nil && resp != nil {
-			if resp.StatusCode >= 200 && resp.StatusCode <= 300 {
-				return nil
-			}
-			if resp.StatusCode == 409 {
-				return err{code: ErrConflict, msg: "Conflict, version does not match", req: req, resp: resp}
-			}
-			if resp.StatusCode == 404 {
-				return err{code: ErrNotFound, msg: "Account does not exist", req: req, resp: resp}
-			}
-		}
-	}
-	if er != nil {
-		return er
-	}
-	return err{code: ErrRequest, msg: "Request failed", cause: e, req: req, resp: resp}
+	return call(ctx, c, opDeleteAccount, http.MethodDelete, uri, (*any)(nil), (*any)(nil))
 }
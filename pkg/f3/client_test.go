@@ -1,16 +1,24 @@
 package f3_test
 
 import (
+	"context"
+	"errors"
 	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 //
-// No tests here, because except for NewClient all other methods require integration tests or mock-ups and
+// Only few tests here, because except for NewClient all other methods require integration tests or mock-ups and
 // I tend to agree to the author of this:
 //
 // https://medium.com/@thrawn01/why-you-should-never-test-private-methods-f822358e010
 //
+// Behaviour that does not depend upon the account API, like the handling of contexts, is tested against a local
+// test server.
+//
 
 func TestNewClient(t *testing.T) {
 	client := f3.NewClient()
@@ -19,3 +27,63 @@ func TestNewClient(t *testing.T) {
 		t.Fatalf("Wrong transport in default HTTP client")
 	}
 }
+
+// newSlowServer returns a test server that does not answer before the request is canceled.
+func newSlowServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+}
+
+func TestClient_FetchAccountWithContext_Deadline(t *testing.T) {
+	server := newSlowServer()
+	defer server.Close()
+	client := f3.NewClient().WithEndPoint(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	fetched, e := client.FetchAccountWithContext(ctx, f3.IntegrationTestAccountId)
+	if fetched != nil {
+		t.Errorf("Fetched an account from a server that never answers")
+	}
+	if e == nil {
+		t.Fatalf("Missing error")
+	}
+	if e.ErrorCode() != f3.ErrTimeout {
+		t.Errorf("Invalid error, expected %d, got %d", f3.ErrTimeout, e.ErrorCode())
+	}
+	if !errors.Is(e.Unwrap(), context.DeadlineExceeded) {
+		t.Errorf("Expected the cause to be the deadline, but was: %v", e.Unwrap())
+	}
+}
+
+func TestClient_DeleteAccountWithContext_Canceled(t *testing.T) {
+	server := newSlowServer()
+	defer server.Close()
+	client := f3.NewClient().WithEndPoint(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	e := client.DeleteAccountWithContext(ctx, f3.IntegrationTestAccountId, 0)
+	if e == nil {
+		t.Fatalf("Missing error")
+	}
+	if e.ErrorCode() != f3.ErrCanceled {
+		t.Errorf("Invalid error, expected %d, got %d", f3.ErrCanceled, e.ErrorCode())
+	}
+	if !errors.Is(e.Unwrap(), context.Canceled) {
+		t.Errorf("Expected the cause to be the cancellation, but was: %v", e.Unwrap())
+	}
+}
+
+func TestClient_IsHealthyWithContext_Canceled(t *testing.T) {
+	server := newSlowServer()
+	defer server.Close()
+	client := f3.NewClient().WithEndPoint(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if client.IsHealthyWithContext(ctx) {
+		t.Errorf("A canceled health check must not report healthy")
+	}
+}
@@ -1,6 +1,7 @@
 package f3
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -48,6 +49,12 @@ func (e err) Unwrap() error {
 	return e.cause
 }
 
+// Is returns true, if the target is the sentinel of the code of the error.
+func (e err) Is(target error) bool {
+	s, ok := target.(sentinel)
+	return ok && int(s) == e.code
+}
+
 const (
 	// ErrGeneric signals a generic error.
 	ErrGeneric int = iota
@@ -66,4 +73,136 @@ const (
 
 	// ErrConflict is returned when an invalid version was provided given, normally this means concurrent access.
 	ErrConflict = iota
+
+	// ErrCanceled is returned when the context of a request was canceled before the request finished. The cause is the
+	// error of the context. If the deadline of the context exceeded, ErrTimeout is returned instead.
+	ErrCanceled = iota
+
+	// ErrCircuitOpen is returned when the circuit breaker of the client is open and the request was therefore not sent.
+	ErrCircuitOpen = iota
+
+	// ErrThrottled is returned when the client side limits are exhausted and the client is configured to fail fast.
+	ErrThrottled = iota
+
+	// ErrUnauthorized is returned when the account API rejected the credentials of the request (HTTP 401).
+	ErrUnauthorized = iota
+
+	// ErrForbidden is returned when the account API denied the access to the resource (HTTP 403).
+	ErrForbidden = iota
+
+	// ErrRateLimited is returned when the account API rejected the request, because too many requests were sent
+	// (HTTP 429).
+	ErrRateLimited = iota
+
+	// ErrServer is returned when the account API failed to process the request (HTTP 5xx).
+	ErrServer = iota
+
+	// ErrTimeout is returned when the deadline of the context or the timeout of the HTTP client exceeded before the
+	// request finished. If caused by the context, the cause is the error of the context.
+	ErrTimeout = iota
 )
+
+// MaxErrorBody is the maximal amount of bytes of the response body copied into an ApiError.
+const MaxErrorBody = 4096
+
+// ApiError is the cause of the errors returned for responses with an error status, it can be obtained with
+// errors.As. The body of the response is closed, when the error is returned, therefore a bounded copy is kept.
+type ApiError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// ErrorCode is the unique identifier of the error reported by the account API, if any.
+	ErrorCode string
+
+	// ErrorMessage is the human-readable error reported by the account API, if any.
+	ErrorMessage string
+
+	// Body is a copy of up to MaxErrorBody bytes of the response body.
+	Body []byte
+}
+
+// newApiError returns the ApiError for the given status and response body.
+func newApiError(statusCode int, body []byte) *ApiError {
+	apiError := &ApiError{StatusCode: statusCode}
+	var errResponse ErrorResponse
+	if json.Unmarshal(body, &errResponse) == nil {
+		apiError.ErrorCode = errResponse.ErrorCode
+		apiError.ErrorMessage = errResponse.ErrorMessage
+	}
+	if len(body) > MaxErrorBody {
+		body = body[:MaxErrorBody]
+	}
+	apiError.Body = append([]byte(nil), body...)
+	return apiError
+}
+
+func (a *ApiError) Error() string {
+	if len(a.ErrorMessage) > 0 {
+		return a.ErrorMessage
+	}
+	return fmt.Sprintf("%d %s", a.StatusCode, http.StatusText(a.StatusCode))
+}
+
+// sentinel is an error matching every Err with the same code, see Is.
+type sentinel int
+
+func (s sentinel) Error() string {
+	return fmt.Sprintf("error code %d", int(s))
+}
+
+// Sentinels matching the Err with the corresponding code, when used with errors.Is:
+//
+//	if errors.Is(e, f3.NotFoundError) {
+//		...
+//	}
+var (
+	GenericError      error = sentinel(ErrGeneric)
+	RequestError      error = sentinel(ErrRequest)
+	ResponseError     error = sentinel(ErrResponse)
+	NotFoundError     error = sentinel(ErrNotFound)
+	BadRequestError   error = sentinel(ErrBadRequest)
+	ConflictError     error = sentinel(ErrConflict)
+	CanceledError     error = sentinel(ErrCanceled)
+	CircuitOpenError  error = sentinel(ErrCircuitOpen)
+	ThrottledError    error = sentinel(ErrThrottled)
+	UnauthorizedError error = sentinel(ErrUnauthorized)
+	ForbiddenError    error = sentinel(ErrForbidden)
+	RateLimitedError  error = sentinel(ErrRateLimited)
+	ServerError       error = sentinel(ErrServer)
+	TimeoutError      error = sentinel(ErrTimeout)
+)
+
+// VersionConflict is the cause of ErrConflict returned by Client.UpdateAccount and Client.DeleteAccountLatest, when
+// all attempts to modify the account were rejected, because the account was modified concurrently.
+type VersionConflict struct {
+	// AccountId is the id of the account that could not be updated.
+	AccountId string
+
+	// Version is the last version of the account seen before the final attempt was rejected.
+	Version uint64
+
+	// Attempts is the amount of attempts made.
+	Attempts int
+}
+
+func (v *VersionConflict) Error() string {
+	return fmt.Sprintf("Conflict, account %s was modified concurrently, last seen version %d after %d attempts",
+		v.AccountId, v.Version, v.Attempts)
+}
+
+// SubmissionFailed is the cause of ErrBadRequest returned by Client.AwaitAccountRequestSubmission, when the delivery
+// of the submission failed.
+type SubmissionFailed struct {
+	// SubmissionId is the id of the failed submission.
+	SubmissionId string
+
+	// Status is the terminal status of the submission.
+	Status SubmissionStatusString
+
+	// Reason is the status reason reported by Form3, if any.
+	Reason string
+}
+
+func (s *SubmissionFailed) Error() string {
+	return fmt.Sprintf("Submission %s ended with status %s: %s", s.SubmissionId, s.Status, s.Reason)
+}
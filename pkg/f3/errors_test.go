@@ -0,0 +1,90 @@
+package f3_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		code     int
+		sentinel error
+	}{
+		{http.StatusBadRequest, f3.ErrBadRequest, f3.BadRequestError},
+		{http.StatusUnauthorized, f3.ErrUnauthorized, f3.UnauthorizedError},
+		{http.StatusForbidden, f3.ErrForbidden, f3.ForbiddenError},
+		{http.StatusNotFound, f3.ErrNotFound, f3.NotFoundError},
+		{http.StatusConflict, f3.ErrConflict, f3.ConflictError},
+		{http.StatusTooManyRequests, f3.ErrRateLimited, f3.RateLimitedError},
+		{http.StatusInternalServerError, f3.ErrServer, f3.ServerError},
+		{http.StatusServiceUnavailable, f3.ErrServer, f3.ServerError},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(`{"error_code":"2f1a8a0e-0e5c-4b5b-9f9e-4a1c3e6b7d8f","error_message":"rejected"}`))
+			}))
+			defer server.Close()
+			client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(f3.NoRetry))
+
+			_, e := client.FetchAccount(f3.IntegrationTestAccountId)
+			if e == nil || e.ErrorCode() != test.code {
+				t.Fatalf("Expected the error code %d, but got: %v", test.code, e)
+			}
+			if !errors.Is(e, test.sentinel) {
+				t.Errorf("Expected the error to match its sentinel")
+			}
+			if errors.Is(e, f3.GenericError) {
+				t.Errorf("Expected the error not to match another sentinel")
+			}
+			var apiError *f3.ApiError
+			if !errors.As(e, &apiError) {
+				t.Fatalf("Expected an ApiError as cause, but got: %v", e.Unwrap())
+			}
+			if apiError.StatusCode != test.status || apiError.ErrorCode != "2f1a8a0e-0e5c-4b5b-9f9e-4a1c3e6b7d8f" ||
+				apiError.ErrorMessage != "rejected" || !strings.Contains(string(apiError.Body), "rejected") {
+				t.Errorf("Captured the wrong error: %+v", apiError)
+			}
+		})
+	}
+}
+
+func TestClient_ErrorBodyBounded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(strings.Repeat("x", 10*f3.MaxErrorBody)))
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	_, e := client.FetchAccount(f3.IntegrationTestAccountId)
+	var apiError *f3.ApiError
+	if !errors.As(e, &apiError) {
+		t.Fatalf("Expected an ApiError as cause, but got: %v", e)
+	}
+	if len(apiError.Body) != f3.MaxErrorBody {
+		t.Errorf("Expected the body to be cut at %d bytes, but got %d", f3.MaxErrorBody, len(apiError.Body))
+	}
+	if apiError.Error() != "400 Bad Request" {
+		t.Errorf("Expected the status as message, if the body is no API error, but got: %s", apiError.Error())
+	}
+}
+
+func TestClient_HttpClientTimeout(t *testing.T) {
+	server := newSlowServer()
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithTimeout(20*time.Millisecond), f3.WithRetryPolicy(f3.NoRetry))
+
+	_, e := client.FetchAccount(f3.IntegrationTestAccountId)
+	if e == nil || e.ErrorCode() != f3.ErrTimeout || !errors.Is(e, f3.TimeoutError) {
+		t.Fatalf("Expected a timeout, but got: %v", e)
+	}
+}
@@ -0,0 +1,133 @@
+package f3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// errThrottled is returned by send, when the client side limits are exhausted and the client should not wait.
+var errThrottled = errors.New("the client side request limit is exhausted")
+
+// Limits configures the client side limits of a client, which are shared by all goroutines using the client. Every
+// attempt to send a request, including retries, requires a token from the bucket and a free in-flight slot, which is
+// occupied until the response body is closed.
+type Limits struct {
+	// RequestsPerSecond is the rate at which the token bucket is refilled; zero disables the rate limit.
+	RequestsPerSecond float64
+
+	// Burst is the size of the token bucket, so the amount of requests that can be sent at once. Values less than
+	// one are treated as one.
+	Burst int
+
+	// MaxInFlight is the maximal amount of requests sent concurrently; zero disables the limit.
+	MaxInFlight int
+
+	// FailFast makes requests fail immediately with ErrThrottled, when no token or in-flight slot is available.
+	// Otherwise, requests wait until the budget is available again or their context is done.
+	FailFast bool
+}
+
+// WithLimits enables the given client side limits. By default, the client is not limited.
+func WithLimits(limits Limits) Option {
+	return func(c *Client) {
+		l := &limiter{limits: limits}
+		if limits.RequestsPerSecond > 0 {
+			if l.limits.Burst < 1 {
+				l.limits.Burst = 1
+			}
+			l.tokens = float64(l.limits.Burst)
+			l.last = time.Now()
+		}
+		if limits.MaxInFlight > 0 {
+			l.slots = make(chan struct{}, limits.MaxInFlight)
+		}
+		c.limiter = l
+	}
+}
+
+// limiter is the implementation of the client side limits.
+type limiter struct {
+	limits Limits
+	mutex  sync.Mutex
+	tokens float64
+	last   time.Time
+	slots  chan struct{}
+}
+
+// acquire waits for an in-flight slot and a token and returns the function to release the slot again. The slot is
+// acquired first, so that no token is spent by a request that can't be sent. If the limits are exhausted and the
+// client should fail fast, errThrottled is returned; if the context is done while waiting, the error of the context is
+// returned.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	l := c.limiter
+	if l == nil {
+		return func() {}, nil
+	}
+	release := func() {}
+	if l.slots != nil {
+		if l.limits.FailFast {
+			select {
+			case l.slots <- struct{}{}:
+			default:
+				return nil, errThrottled
+			}
+		} else {
+			select {
+			case l.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-l.slots })
+		}
+	}
+	if e := l.take(ctx); e != nil {
+		release()
+		return nil, e
+	}
+	return release, nil
+}
+
+// take takes a token from the bucket, waiting for it to be refilled if necessary.
+func (l *limiter) take(ctx context.Context) error {
+	if l.limits.RequestsPerSecond <= 0 {
+		return nil
+	}
+	for {
+		l.mutex.Lock()
+		now := time.Now()
+		l.tokens = math.Min(float64(l.limits.Burst), l.tokens+now.Sub(l.last).Seconds()*l.limits.RequestsPerSecond)
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mutex.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.limits.RequestsPerSecond * float64(time.Second))
+		l.mutex.Unlock()
+		if l.limits.FailFast {
+			return errThrottled
+		}
+		if e := sleep(ctx, wait); e != nil {
+			return e
+		}
+	}
+}
+
+// releasingBody is a response body that releases the in-flight slot, when being closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b releasingBody) Close() error {
+	e := b.ReadCloser.Close()
+	b.release()
+	return e
+}
@@ -0,0 +1,107 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Limits_MaxInFlight(t *testing.T) {
+	account := createTestAccount(true)
+	blocked := make(chan struct{})
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			blocked <- struct{}{}
+			<-unblock
+		}
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLimits(f3.Limits{MaxInFlight: 1, FailFast: true}))
+
+	done := make(chan f3.Err)
+	go func() {
+		done <- client.DeleteAccount(account.Id, 0)
+	}()
+	<-blocked
+	if _, e := client.FetchAccount(account.Id); e == nil || e.ErrorCode() != f3.ErrThrottled {
+		t.Errorf("Expected the request to be throttled, got: %v", e)
+	}
+	close(unblock)
+	if e := <-done; e != nil {
+		t.Fatalf("Failed to delete the account: %s", e.Error())
+	}
+	for i := 0; i < 3; i++ {
+		if _, e := client.FetchAccount(account.Id); e != nil {
+			t.Fatalf("The in-flight slot was not released: %s", e.Error())
+		}
+	}
+}
+
+func TestClient_Limits_RequestsPerSecond(t *testing.T) {
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLimits(f3.Limits{RequestsPerSecond: 20, Burst: 1}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, e := client.FetchAccount(account.Id); e != nil {
+			t.Fatalf("Failed to fetch the account: %s", e.Error())
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected the requests to be limited to 20 per second, but 3 requests took only %v", elapsed)
+	}
+
+	client = f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLimits(f3.Limits{RequestsPerSecond: 1, Burst: 1}))
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, e := client.FetchAccountWithContext(ctx, account.Id); e == nil || e.ErrorCode() != f3.ErrTimeout {
+		t.Errorf("Expected the waiting request to time out, got: %v", e)
+	}
+}
+
+func TestClient_Limits_ThrottledRequestKeepsToken(t *testing.T) {
+	account := createTestAccount(true)
+	blocked := make(chan struct{})
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			blocked <- struct{}{}
+			<-unblock
+		}
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+	limits := f3.Limits{RequestsPerSecond: 0.001, Burst: 2, MaxInFlight: 1, FailFast: true}
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLimits(limits))
+
+	done := make(chan f3.Err)
+	go func() {
+		done <- client.DeleteAccount(account.Id, 0)
+	}()
+	<-blocked
+	for i := 0; i < 3; i++ {
+		if _, e := client.FetchAccount(account.Id); e == nil || e.ErrorCode() != f3.ErrThrottled {
+			t.Errorf("Expected the request to be throttled, got: %v", e)
+		}
+	}
+	close(unblock)
+	if e := <-done; e != nil {
+		t.Fatalf("Failed to delete the account: %s", e.Error())
+	}
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Errorf("Requests throttled by the in-flight limit must not spend tokens, got: %s", e.Error())
+	}
+}
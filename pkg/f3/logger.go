@@ -0,0 +1,180 @@
+package f3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxLoggedBody is the maximal amount of bytes of a body that are inspected for logging.
+const maxLoggedBody = 64 * 1024
+
+// LogLevel is the severity of a log record.
+type LogLevel int
+
+const (
+	// LogDebug is used for records that are only of interest while debugging.
+	LogDebug LogLevel = iota
+
+	// LogInfo is used for records about normal operation.
+	LogInfo
+
+	// LogWarn is used for records about failures that are reported to the caller.
+	LogWarn
+
+	// LogError is used for records about failures the caller may not be aware of.
+	LogError
+)
+
+// String returns the human-readable name of the level.
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+// Logger is the interface the client writes structured log records to. The fields are alternating key/value pairs,
+// where the keys are always strings, so that the interface can easily be adapted to any structured logging library.
+type Logger interface {
+	// Log writes a single record with the given level, message and fields.
+	Log(level LogLevel, msg string, fields ...any)
+}
+
+// LoggerFunc is an adapter to allow the use of an ordinary function as Logger.
+type LoggerFunc func(level LogLevel, msg string, fields ...any)
+
+// Log calls f(level, msg, fields...).
+func (f LoggerFunc) Log(level LogLevel, msg string, fields ...any) {
+	f(level, msg, fields...)
+}
+
+// NewStdLogger returns a Logger that writes all records with at least the given level as a single line with key=value
+// pairs to the given standard logger. If no standard logger is given, the standard logger of the log package is used.
+func NewStdLogger(std *log.Logger, minLevel LogLevel) Logger {
+	if std == nil {
+		std = log.Default()
+	}
+	return LoggerFunc(func(level LogLevel, msg string, fields ...any) {
+		if level < minLevel {
+			return
+		}
+		var sb strings.Builder
+		sb.WriteString(level.String())
+		sb.WriteByte(' ')
+		sb.WriteString(msg)
+		for i := 0; i+1 < len(fields); i += 2 {
+			_, _ = fmt.Fprintf(&sb, " %v=%q", fields[i], fmt.Sprint(fields[i+1]))
+		}
+		std.Print(sb.String())
+	})
+}
+
+// log writes a record to the logger of the client, if any.
+func (c *Client) log(level LogLevel, msg string, fields ...any) {
+	if c.logger != nil {
+		c.logger.Log(level, msg, fields...)
+	}
+}
+
+// WithPayloadLogging enables logging of the request and response bodies with LogDebug. The values of the redacted
+// fields are replaced, see WithRedactedFields.
+func WithPayloadLogging() Option {
+	return func(c *Client) {
+		c.logPayloads = true
+	}
+}
+
+// logExchange logs the outcome of a single attempt to send a request. Failed requests and error responses are logged
+// with LogWarn, including the error message returned by the account API, successful requests with LogDebug. Only the
+// path of the request is logged, never the query, because it may contain sensitive filters.
+func (c *Client) logExchange(req *http.Request, resp *http.Response, e error, attempt int, latency time.Duration) {
+	if c.logger == nil {
+		return
+	}
+	fields := []any{
+		"operation", OperationName(req.Context()),
+		"method", req.Method,
+		"path", req.URL.Path,
+		"attempt", attempt,
+		"latency", latency,
+	}
+	var reqBody, respBody []byte
+	if c.logPayloads && req.GetBody != nil {
+		if body, bodyErr := req.GetBody(); bodyErr == nil {
+			reqBody, _ = ioutil.ReadAll(io.LimitReader(body, maxLoggedBody))
+			_ = body.Close()
+		}
+	}
+	if e == nil && (c.logPayloads || resp.StatusCode >= 400) {
+		respBody = peekBody(resp, maxLoggedBody)
+	}
+	r := newRedactor(c.redactedFields, reqBody)
+	if e != nil {
+		fields = append(fields, "error", r.text(errorText(e)))
+	} else {
+		fields = append(fields, "status", resp.StatusCode)
+		if resp.StatusCode >= 400 {
+			var errResponse ErrorResponse
+			if json.Unmarshal(respBody, &errResponse) == nil && len(errResponse.ErrorMessage) > 0 {
+				fields = append(fields, "error_message", r.text(errResponse.ErrorMessage))
+			}
+		}
+	}
+	if c.logPayloads {
+		if reqBody != nil {
+			fields = append(fields, "request_body", r.payload(reqBody))
+		}
+		if respBody != nil {
+			fields = append(fields, "response_body", r.payload(respBody))
+		}
+	}
+	switch {
+	case e != nil:
+		c.log(LogWarn, "Request failed", fields...)
+	case resp.StatusCode >= 400:
+		c.log(LogWarn, "Request rejected", fields...)
+	default:
+		c.log(LogDebug, "Request completed", fields...)
+	}
+}
+
+// errorText returns the message of the given error to be logged. The message of an url.Error contains the full URL of
+// the request, including the query with the filters, so only its operation and the underlying error are returned.
+func errorText(e error) string {
+	if urlErr, ok := e.(*url.Error); ok && urlErr.Err != nil {
+		return urlErr.Op + ": " + urlErr.Err.Error()
+	}
+	return e.Error()
+}
+
+// peekBody reads up to limit bytes from the body of the response and returns them. The body of the response is
+// replaced, so that the read bytes can be read again.
+func peekBody(resp *http.Response, limit int64) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	peeked, _ := ioutil.ReadAll(io.LimitReader(resp.Body, limit))
+	resp.Body = peekedBody{io.MultiReader(bytes.NewReader(peeked), resp.Body), resp.Body}
+	return peeked
+}
+
+// peekedBody is a response body of which the beginning was already read.
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
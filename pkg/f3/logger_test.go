@@ -0,0 +1,162 @@
+package f3_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newRejectingServer returns a test server that rejects all requests with the given error message.
+func newRejectingServer(message string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(w, `{"error_message":%q}`, message)
+	}))
+}
+
+func TestClient_WithLogger_Redaction(t *testing.T) {
+	account := createTestAccount(true)
+	server := newRejectingServer("account_number " + account.Attr.AccountNumber + " is invalid")
+	defer server.Close()
+
+	var buffer bytes.Buffer
+	logger := f3.NewStdLogger(log.New(&buffer, "", 0), f3.LogDebug)
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLogger(logger), f3.WithPayloadLogging())
+	if _, e := client.CreateAccount(account); e == nil {
+		t.Fatalf("Created an account, even while the server rejected it")
+	}
+
+	line := buffer.String()
+	for _, expected := range []string{
+		`WARN Request rejected`,
+		`operation="create_account"`,
+		`method="POST"`,
+		`path="/organisation/accounts"`,
+		`attempt="1"`,
+		`status="400"`,
+		`error_message="account_number [REDACTED] is invalid"`,
+		`"country\":\"GB\"`,
+	} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("Expected the log to contain %s, but got: %s", expected, line)
+		}
+	}
+	for _, secret := range []string{account.Attr.AccountNumber, account.Attr.Name[0], *account.Attr.CustomerId} {
+		if strings.Contains(line, secret) {
+			t.Errorf("The log contains the sensitive value %s: %s", secret, line)
+		}
+	}
+}
+
+func TestClient_WithRedactedFields_Disabled(t *testing.T) {
+	account := createTestAccount(true)
+	server := newRejectingServer("account_number " + account.Attr.AccountNumber + " is invalid")
+	defer server.Close()
+
+	var messages []string
+	logger := f3.LoggerFunc(func(level f3.LogLevel, msg string, fields ...any) {
+		for i := 0; i+1 < len(fields); i += 2 {
+			if fields[i] == "error_message" {
+				messages = append(messages, fmt.Sprint(fields[i+1]))
+			}
+		}
+	})
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLogger(logger), f3.WithRedactedFields())
+	if _, e := client.CreateAccount(account); e == nil {
+		t.Fatalf("Created an account, even while the server rejected it")
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], account.Attr.AccountNumber) {
+		t.Errorf("Expected the unredacted error message to be logged, but got: %v", messages)
+	}
+}
+
+func TestClient_WithLogger_NoQueryInErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL
+	server.Close()
+
+	var buffer bytes.Buffer
+	logger := f3.NewStdLogger(log.New(&buffer, "", 0), f3.LogDebug)
+	client := f3.NewClient(f3.WithEndPoint(endpoint), f3.WithLogger(logger), f3.WithRetryPolicy(fastRetryPolicy))
+	iban := "GB11NWBK40030041426819"
+	if _, e := client.ListAccounts(&f3.AccountFilter{Iban: []string{iban}}, nil); e == nil {
+		t.Fatalf("Listed accounts, even while the server is down")
+	}
+
+	line := buffer.String()
+	for _, expected := range []string{`WARN Request failed`, `INFO Retry request`, `error="Get: `} {
+		if !strings.Contains(line, expected) {
+			t.Errorf("Expected the log to contain %s, but got: %s", expected, line)
+		}
+	}
+	if strings.Contains(line, iban) {
+		t.Errorf("The log contains the filter value %s: %s", iban, line)
+	}
+}
+
+func TestClient_WithLogger_RedactsPersonalData(t *testing.T) {
+	account := createTestAccount(true)
+	customerId := "customer-4711"
+	account.Attr = &f3.AccountAttr{
+		AlternativeNames:            []string{"Alex Weber"},
+		AlternativeBankAccountNames: []string{"A. Lowey-Weber"},
+		Name:                        []string{"Alexander Lowey-Weber"},
+		AccountNumber:               "41426819",
+		BankAccountName:             "Alexander L. Weber",
+		BankId:                      "400300",
+		BankIdCode:                  "GBDSC",
+		BaseCurrency:                "GBP",
+		CustomerId:                  &customerId,
+		Bic:                         "NWBKGB22",
+		Country:                     "GB",
+		FirstName:                   "Alexander",
+		Title:                       "Dr",
+		Iban:                        "GB11NWBK40030041426819",
+		PrivateIdentification: &f3.PrivateIdentification{
+			Address:        []string{"10 Downing Street"},
+			BirthCountry:   "GB",
+			BirthDate:      "1970-01-31",
+			City:           "Westminster",
+			Identification: "AB123456C",
+		},
+		OrganisationIdentification: &f3.OrganisationIdentification{
+			Actors: []*f3.OrganisationIdentificationActor{
+				{Name: []string{"Jane Director"}, BirthDate: "1980-12-24", Residency: "Scotland", Role: "director"},
+			},
+			Country: "GB",
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	var buffer bytes.Buffer
+	logger := f3.NewStdLogger(log.New(&buffer, "", 0), f3.LogDebug)
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithLogger(logger), f3.WithPayloadLogging())
+	if _, e := client.CreateAccount(account); e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+
+	line := buffer.String()
+	if !strings.Contains(line, "request_body") || !strings.Contains(line, "response_body") {
+		t.Fatalf("Expected the payloads to be logged, but got: %s", line)
+	}
+	for _, secret := range []string{
+		"Alex Weber", "A. Lowey-Weber", "Alexander Lowey-Weber", "41426819", "Alexander L. Weber", customerId,
+		`"Alexander"`, `"Dr"`, "GB11NWBK40030041426819", "10 Downing Street", "1970-01-31", "Westminster",
+		"AB123456C", "Jane Director", "1980-12-24", "Scotland",
+	} {
+		if strings.Contains(line, secret) {
+			t.Errorf("The log contains the personal data %s: %s", secret, line)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package f3
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram buckets in seconds, used when creating metrics
+// without explicit buckets.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects metrics about the operations of one or more clients. The metrics can be read programmatically using
+// Snapshot or served in the Prometheus text exposition format, because Metrics implements http.Handler.
+type Metrics struct {
+	buckets     []float64
+	mutex       sync.Mutex
+	operations  map[string]*operationMetrics
+	connections map[bool]uint64
+	inFlight    int64
+}
+
+// operationMetrics are the collected metrics of a single operation.
+type operationMetrics struct {
+	requests uint64
+	errors   map[int]uint64
+	counts   []uint64
+	sum      float64
+}
+
+// MetricsSnapshot is a point-in-time copy of the collected metrics.
+type MetricsSnapshot struct {
+	// Operations are the metrics by operation name, like "fetch_account".
+	Operations map[string]OperationMetrics
+
+	// NewConnections is the amount of new connections opened by the transport.
+	NewConnections uint64
+
+	// ReusedConnections is the amount of requests that reused an idle connection of the transport.
+	ReusedConnections uint64
+
+	// InFlight is the amount of requests currently waiting for a response.
+	InFlight int64
+}
+
+// OperationMetrics are the metrics of a single operation.
+type OperationMetrics struct {
+	// Requests is the amount of finished operations, including the failed ones.
+	Requests uint64
+
+	// Errors is the amount of failed operations by error code, see ErrGeneric and following.
+	Errors map[int]uint64
+
+	// Latency is the histogram of the duration of the operations, including all retries.
+	Latency Histogram
+}
+
+// Histogram is a snapshot of a latency histogram.
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets in seconds.
+	Buckets []float64
+
+	// Counts are the amounts of observations per bucket, not cumulative. The last count is for observations above
+	// the last bucket.
+	Counts []uint64
+
+	// Sum is the sum of all observations in seconds.
+	Sum float64
+}
+
+// Count returns the total amount of observations.
+func (h Histogram) Count() uint64 {
+	var count uint64
+	for _, c := range h.Counts {
+		count += c
+	}
+	return count
+}
+
+// NewMetrics creates a new metrics collector with the given upper bounds for the latency buckets in seconds. If no
+// buckets are given, the DefaultLatencyBuckets are used.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Metrics{buckets: sorted, operations: map[string]*operationMetrics{}, connections: map[bool]uint64{}}
+}
+
+// WithMetrics makes the client collect metrics into the given collector. The same collector can be shared by multiple
+// clients.
+func WithMetrics(metrics *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// Metrics returns the metrics collector of the client or nil, if the client does not collect metrics.
+func (c *Client) Metrics() *Metrics {
+	return c.metrics
+}
+
+// observe records a finished operation.
+func (m *Metrics) observe(operation string, latency time.Duration, er Err) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	om := m.operations[operation]
+	if om == nil {
+		om = &operationMetrics{errors: map[int]uint64{}, counts: make([]uint64, len(m.buckets)+1)}
+		m.operations[operation] = om
+	}
+	om.requests++
+	if er != nil {
+		om.errors[er.ErrorCode()]++
+	}
+	seconds := latency.Seconds()
+	om.counts[sort.SearchFloat64s(m.buckets, seconds)]++
+	om.sum += seconds
+}
+
+// trace returns a client trace that records the usage of the connections of the transport.
+func (m *Metrics) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			m.connections[info.Reused]++
+		},
+	}
+}
+
+// begin records the start of a request waiting for a response and returns the function to record its end.
+func (m *Metrics) begin() func() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.inFlight++
+	return func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.inFlight--
+	}
+}
+
+// Snapshot returns a copy of the collected metrics.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	snapshot := MetricsSnapshot{
+		Operations:        make(map[string]OperationMetrics, len(m.operations)),
+		NewConnections:    m.connections[false],
+		ReusedConnections: m.connections[true],
+		InFlight:          m.inFlight,
+	}
+	for name, om := range m.operations {
+		errs := make(map[int]uint64, len(om.errors))
+		for code, count := range om.errors {
+			errs[code] = count
+		}
+		snapshot.Operations[name] = OperationMetrics{
+			Requests: om.requests,
+			Errors:   errs,
+			Latency: Histogram{
+				Buckets: append([]float64(nil), m.buckets...),
+				Counts:  append([]uint64(nil), om.counts...),
+				Sum:     om.sum,
+			},
+		}
+	}
+	return snapshot
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(headerContentType, "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format to the given writer.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	snapshot := m.Snapshot()
+	names := make([]string, 0, len(snapshot.Operations))
+	for name := range snapshot.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := &countingWriter{w: bufio.NewWriter(w)}
+	out.printf("# HELP f3_client_requests_total Total number of finished client operations.\n")
+	out.printf("# TYPE f3_client_requests_total counter\n")
+	for _, name := range names {
+		out.printf("f3_client_requests_total{operation=%q} %d\n", name, snapshot.Operations[name].Requests)
+	}
+	out.printf("# HELP f3_client_errors_total Total number of failed client operations by error code.\n")
+	out.printf("# TYPE f3_client_errors_total counter\n")
+	for _, name := range names {
+		errs := snapshot.Operations[name].Errors
+		codes := make([]int, 0, len(errs))
+		for code := range errs {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			out.printf("f3_client_errors_total{operation=%q,code=\"%d\"} %d\n", name, code, errs[code])
+		}
+	}
+	out.printf("# HELP f3_client_request_duration_seconds Duration of client operations including retries.\n")
+	out.printf("# TYPE f3_client_request_duration_seconds histogram\n")
+	for _, name := range names {
+		latency := snapshot.Operations[name].Latency
+		var cumulative uint64
+		for i, count := range latency.Counts {
+			cumulative += count
+			le := "+Inf"
+			if i < len(latency.Buckets) {
+				le = formatFloat(latency.Buckets[i])
+			}
+			out.printf("f3_client_request_duration_seconds_bucket{operation=%q,le=%q} %d\n", name, le, cumulative)
+		}
+		out.printf("f3_client_request_duration_seconds_sum{operation=%q} %s\n", name, formatFloat(latency.Sum))
+		out.printf("f3_client_request_duration_seconds_count{operation=%q} %d\n", name, cumulative)
+	}
+	out.printf("# HELP f3_client_connections_total Total number of connections obtained from the transport.\n")
+	out.printf("# TYPE f3_client_connections_total counter\n")
+	out.printf("f3_client_connections_total{reused=\"false\"} %d\n", snapshot.NewConnections)
+	out.printf("f3_client_connections_total{reused=\"true\"} %d\n", snapshot.ReusedConnections)
+	out.printf("# HELP f3_client_requests_in_flight Number of requests waiting for a response.\n")
+	out.printf("# TYPE f3_client_requests_in_flight gauge\n")
+	out.printf("f3_client_requests_in_flight %d\n", snapshot.InFlight)
+	if out.e == nil {
+		out.e = out.w.Flush()
+	}
+	return out.n, out.e
+}
+
+// formatFloat formats a float the way Prometheus expects it.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// countingWriter counts the written bytes and remembers the first error.
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+	e error
+}
+
+func (cw *countingWriter) printf(format string, args ...any) {
+	if cw.e != nil {
+		return
+	}
+	n, e := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.e = e
+}
@@ -0,0 +1,67 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithMetrics(t *testing.T) {
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	metrics := f3.NewMetrics(0.1, 1)
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithTransport(&http.Transport{}), f3.WithMetrics(metrics))
+
+	for i := 0; i < 3; i++ {
+		if _, e := client.FetchAccount(account.Id); e != nil {
+			t.Fatalf("Failed to fetch the account: %s", e.Error())
+		}
+	}
+	if e := client.DeleteAccount(account.Id, 0); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Fatalf("Expected the account to not be found, got: %v", e)
+	}
+
+	snapshot := metrics.Snapshot()
+	fetch := snapshot.Operations["fetch_account"]
+	if fetch.Requests != 3 || len(fetch.Errors) != 0 || fetch.Latency.Count() != 3 {
+		t.Errorf("Expected 3 successful fetches, got: %+v", fetch)
+	}
+	del := snapshot.Operations["delete_account"]
+	if del.Requests != 1 || del.Errors[f3.ErrNotFound] != 1 {
+		t.Errorf("Expected 1 failed delete, got: %+v", del)
+	}
+	if snapshot.NewConnections != 1 || snapshot.ReusedConnections != 3 {
+		t.Errorf("Expected 1 new and 3 reused connections, got %d and %d", snapshot.NewConnections, snapshot.ReusedConnections)
+	}
+	if snapshot.InFlight != 0 {
+		t.Errorf("Expected no requests in flight, got %d", snapshot.InFlight)
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	text := recorder.Body.String()
+	for _, expected := range []string{
+		`# TYPE f3_client_requests_total counter`,
+		`f3_client_requests_total{operation="fetch_account"} 3`,
+		`f3_client_errors_total{operation="delete_account",code="3"} 1`,
+		`f3_client_request_duration_seconds_bucket{operation="fetch_account",le="+Inf"} 3`,
+		`f3_client_request_duration_seconds_count{operation="delete_account"} 1`,
+		`f3_client_connections_total{reused="true"} 3`,
+		`f3_client_requests_in_flight 0`,
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected the metrics to contain %s, but got:\n%s", expected, text)
+		}
+	}
+}
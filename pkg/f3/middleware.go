@@ -0,0 +1,56 @@
+package f3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// errNoResponse is reported, when a middleware returns neither a response nor an error.
+var errNoResponse = errors.New("neither response nor error received")
+
+// RoundTripFunc sends a single request and returns the response or the error that prevented receiving it.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of requests. It can inspect or modify the request before calling next, and observe or
+// replace the response and error afterwards. Middlewares are invoked for every attempt, including retries, so the
+// request passed to them is always a fresh one.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends the given middlewares to the chain of the client. The first middleware in the chain is the
+// outermost one, so it sees the request first and the response last.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// SetHeader returns a middleware that sets the header with the given name to the given value for all requests.
+func SetHeader(name string, value string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(name, value)
+			return next(req)
+		}
+	}
+}
+
+// operationKey is the context key to store the name of the operation.
+type operationKey struct{}
+
+// OperationName returns the name of the client operation the request context belongs to, for example
+// "fetch_account", or an empty string if the context does not belong to a client operation. This allows middlewares to
+// group requests by operation.
+func OperationName(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
+
+// chain returns the function to send requests through all middlewares of the client.
+func (c *Client) chain() RoundTripFunc {
+	next := RoundTripFunc(c.httpClient.Do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		next = c.middlewares[i](next)
+	}
+	return next
+}
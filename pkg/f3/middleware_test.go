@@ -0,0 +1,61 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithMiddleware(t *testing.T) {
+	var authorization string
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) f3.Middleware {
+		return func(next f3.RoundTripFunc) f3.RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+">"+f3.OperationName(req.Context()))
+				resp, e := next(req)
+				if e == nil {
+					calls = append(calls, name+"<"+resp.Status)
+				}
+				return resp, e
+			}
+		}
+	}
+	client := f3.NewClient(
+		f3.WithEndPoint(server.URL),
+		f3.WithMiddleware(trace("a"), f3.SetHeader("Authorization", "Bearer token")),
+		f3.WithMiddleware(trace("b")))
+
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	expected := "a>fetch_account,b>fetch_account,b<200 OK,a<200 OK"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("Expected the middlewares to be called %s, but got %s", expected, strings.Join(calls, ","))
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("Expected the header to be set by the middleware, but got '%s'", authorization)
+	}
+}
+
+func TestClient_WithMiddleware_NoResponse(t *testing.T) {
+	broken := func(next f3.RoundTripFunc) f3.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, nil
+		}
+	}
+	client := f3.NewClient(f3.WithEndPoint("http://localhost:0/v1"), f3.WithRetryPolicy(f3.NoRetry), f3.WithMiddleware(broken))
+	if _, e := client.FetchAccount(f3.IntegrationTestAccountId); e == nil || e.ErrorCode() != f3.ErrRequest {
+		t.Errorf("Expected the request to fail, got: %v", e)
+	}
+}
@@ -0,0 +1,135 @@
+package f3
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// TypeAccountAmendment is the type for account amendments.
+	TypeAccountAmendment = "account_amendments"
+
+	// TypeAccountAmendmentSubmission is the type for account amendment submissions.
+	TypeAccountAmendmentSubmission = "account_amendment_submissions"
+)
+
+// AccountAmendmentsEnvelope is an envelope for a list of account amendments.
+type AccountAmendmentsEnvelope struct {
+	Data  []*AccountAmendment `json:"data"`
+	Links *Links              `json:"links,omitempty"`
+}
+
+// AccountAmendmentEnvelope is an envelope for a single account amendment.
+type AccountAmendmentEnvelope struct {
+	Data *AccountAmendment `json:"data"`
+}
+
+// AccountAmendment changes the details of an account through the regulated amendment flow. The account is changed
+// asynchronously, when the amendment is submitted.
+type AccountAmendment struct {
+	Resource
+	// Attr are the changes to apply to the account.
+	Attr *AccountAmendmentAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the amended account and, set server side, the submissions of the amendment.
+	Relationships *AccountAmendmentRelationships `json:"relationships,omitempty"`
+}
+
+// AccountAmendmentAttr are the account amendment specific attributes.
+type AccountAmendmentAttr struct {
+	ModifyReason               string                      `json:"modify_reason,omitempty"`
+	Name                       []string                    `json:"name,omitempty"` // Up to four lines.
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+}
+
+// AccountAmendmentRelationships are the relationships of an account amendment.
+type AccountAmendmentRelationships struct {
+	Account                    *AmendmentAccountReferences      `json:"account,omitempty"`
+	AccountAmendmentSubmission *AccountAmendmentSubmissionsData `json:"account_amendment_submission,omitempty"`
+}
+
+// AmendmentAccountReferences holds the reference to the amended account.
+type AmendmentAccountReferences struct {
+	Data []*AmendmentAccountReference `json:"data,omitempty"`
+}
+
+// AmendmentAccountReference refers to a specific version of the amended account.
+type AmendmentAccountReference struct {
+	// Id is the unique identifier of the account.
+	Id string `json:"id,omitempty"`
+
+	// Type is the type of the account, always TypeAccount.
+	Type string `json:"type,omitempty"`
+
+	// Version is the version of the account to amend.
+	Version uint64 `json:"version"`
+}
+
+// AccountAmendmentSubmissionsData embeds the submissions of an account amendment into its relationships.
+type AccountAmendmentSubmissionsData struct {
+	Data []*AccountAmendmentSubmission `json:"data,omitempty"`
+}
+
+// AccountAmendmentSubmissionEnvelope is an envelope for a single account amendment submission.
+type AccountAmendmentSubmissionEnvelope struct {
+	Data *AccountAmendmentSubmission `json:"data"`
+}
+
+// AccountAmendmentSubmission submits an account amendment to Form3, its status tracks the change of the account.
+type AccountAmendmentSubmission struct {
+	Resource
+	// Attr are the attributes of the submission, set server side.
+	Attr *AccountAmendmentSubmissionAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the submitted amendment, set server side.
+	Relationships *AccountAmendmentSubmissionRelationships `json:"relationships,omitempty"`
+}
+
+// AccountAmendmentSubmissionAttr are the account amendment submission specific attributes.
+type AccountAmendmentSubmissionAttr struct {
+	Status             SubmissionStatusString `json:"status,omitempty"`
+	StatusReason       string                 `json:"status_reason,omitempty"`
+	SubmissionDateTime *time.Time             `json:"submission_datetime,omitempty"`
+}
+
+// AccountAmendmentSubmissionRelationships are the relationships of an account amendment submission.
+type AccountAmendmentSubmissionRelationships struct {
+	AccountAmendment *AccountAmendmentsData `json:"account_amendment,omitempty"`
+}
+
+// AccountAmendmentsData embeds the account amendment into the relationships of a submission.
+type AccountAmendmentsData struct {
+	Data []*AccountAmendment `json:"data,omitempty"`
+}
+
+// NewAmendmentAccountReference returns a reference to the current version of the given account.
+func NewAmendmentAccountReference(account *Account) *AmendmentAccountReference {
+	reference := &AmendmentAccountReference{Id: account.Id, Type: TypeAccount}
+	if account.Version != nil {
+		reference.Version = *account.Version
+	}
+	return reference
+}
+
+// NewAccountAmendment is a small helper method to create a new amendment with a new id for the current version of
+// the given account. The changes are added with the With methods:
+//
+//	amendment := f3.NewAccountAmendment(account, "Marriage").WithName("Mrs", "Jane", "Smith")
+func NewAccountAmendment(account *Account, modifyReason string) *AccountAmendment {
+	amendment := new(AccountAmendment)
+	amendment.Type = TypeAccountAmendment
+	amendment.Id = uuid.New().String()
+	amendment.OrganisationId = account.OrganisationId
+	amendment.Attr = &AccountAmendmentAttr{ModifyReason: modifyReason}
+	amendment.Relationships = &AccountAmendmentRelationships{
+		Account: &AmendmentAccountReferences{Data: []*AmendmentAccountReference{NewAmendmentAccountReference(account)}},
+	}
+	return amendment
+}
+
+// WithName changes the name of the account holder to the given lines.
+func (a *AccountAmendment) WithName(name ...string) *AccountAmendment {
+	a.Attr.Name = name
+	return a
+}
@@ -0,0 +1,51 @@
+package f3
+
+import "time"
+
+// RoutingStatusString is an alias for a string that represents the routing status of an account.
+type RoutingStatusString string
+
+const (
+	// RoutingUnroutable represents an account to which payments can't be routed.
+	RoutingUnroutable = RoutingStatusString("unroutable")
+
+	// RoutingRoutable represents an account to which payments can be routed.
+	RoutingRoutable = RoutingStatusString("routable")
+
+	// RoutingDeleted represents a deleted account.
+	RoutingDeleted = RoutingStatusString("deleted")
+
+	// TypeAccountEvent is the type for account events.
+	TypeAccountEvent = "account_events"
+)
+
+// AccountEventsEnvelope is an envelope for a list of account events.
+type AccountEventsEnvelope struct {
+	Data  []*AccountEvent `json:"data"`
+	Links *Links          `json:"links,omitempty"`
+}
+
+// AccountEvent represents a change of the status of an account.
+type AccountEvent struct {
+	Resource
+	// Attr are the attributes of the event.
+	Attr *AccountEventAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the account the event relates to.
+	Relationships *AccountEventRelationships `json:"relationships,omitempty"`
+}
+
+// AccountEventAttr are the account event specific attributes.
+type AccountEventAttr struct {
+	AccountId     string              `json:"account_id,omitempty"`
+	DateTime      *time.Time          `json:"date_time,omitempty"`
+	Description   AccountStatusString `json:"description,omitempty"`
+	Reason        string              `json:"reason,omitempty"` // Only present when the description is StatusFailed.
+	RoutingStatus RoutingStatusString `json:"routing_status,omitempty"`
+	Status        AccountStatusString `json:"status,omitempty"`
+}
+
+// AccountEventRelationships are the relationships of an account event.
+type AccountEventRelationships struct {
+	Account *AccountsEnvelope `json:"account,omitempty"`
+}
@@ -0,0 +1,52 @@
+package f3
+
+import "github.com/google/uuid"
+
+// TypeAccountIdentification is the type for account identifications.
+const TypeAccountIdentification = "account_identifications"
+
+// AccountIdentificationsEnvelope is an envelope for a list of account identifications.
+type AccountIdentificationsEnvelope struct {
+	Data  []*AccountIdentification `json:"data"`
+	Links *Links                   `json:"links,omitempty"`
+}
+
+// AccountIdentificationEnvelope is an envelope for a single account identification.
+type AccountIdentificationEnvelope struct {
+	Data *AccountIdentification `json:"data"`
+}
+
+// AccountIdentification is a secondary identification attached to an account, which is used to match the reference
+// information of payments.
+type AccountIdentification struct {
+	Resource
+	// Attr are the attributes of the identification.
+	Attr *AccountIdentificationAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the account the identification is attached to.
+	Relationships *AccountIdentificationRelationships `json:"relationships,omitempty"`
+}
+
+// AccountIdentificationAttr are the account identification specific attributes.
+type AccountIdentificationAttr struct {
+	SecondaryIdentification string `json:"secondary_identification,omitempty"` // Between 1 and 35 characters.
+}
+
+// AccountIdentificationRelationships are the relationships of an account identification.
+type AccountIdentificationRelationships struct {
+	Account *Relationship `json:"account,omitempty"`
+}
+
+// NewAccountIdentification is a small helper method to create a new account identification with a new id. If the
+// organization-id is nil, then the DefaultOrganizationId is used.
+func NewAccountIdentification(organizationId *string, secondaryIdentification string) *AccountIdentification {
+	identification := new(AccountIdentification)
+	identification.Type = TypeAccountIdentification
+	identification.Id = uuid.New().String()
+	if organizationId == nil {
+		organizationId = &DefaultOrganizationId
+	}
+	identification.OrganisationId = *organizationId
+	identification.Attr = &AccountIdentificationAttr{SecondaryIdentification: secondaryIdentification}
+	return identification
+}
@@ -0,0 +1,140 @@
+package f3
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// SubmissionStatusString is an alias for a string that represents the status of a submission.
+type SubmissionStatusString string
+
+const (
+	// SubmissionAccepted represents a submission accepted by Form3.
+	SubmissionAccepted = SubmissionStatusString("accepted")
+
+	// SubmissionValidationPending represents a submission waiting for its validation.
+	SubmissionValidationPending = SubmissionStatusString("validation_pending")
+
+	// SubmissionValidationPassed represents a submission that passed the validation.
+	SubmissionValidationPassed = SubmissionStatusString("validation_passed")
+
+	// SubmissionReleasedToGateway represents a submission released to the gateway of the scheme.
+	SubmissionReleasedToGateway = SubmissionStatusString("released_to_gateway")
+
+	// SubmissionSubmitted represents a submission submitted to the scheme.
+	SubmissionSubmitted = SubmissionStatusString("submitted")
+
+	// SubmissionDeliveryConfirmed is the terminal status of a successful submission.
+	SubmissionDeliveryConfirmed = SubmissionStatusString("delivery_confirmed")
+
+	// SubmissionDeliveryFailed is the terminal status of a failed submission, the reason is in the status reason.
+	SubmissionDeliveryFailed = SubmissionStatusString("delivery_failed")
+
+	// TypeAccountRequest is the type for account requests.
+	TypeAccountRequest = "account_requests"
+
+	// TypeAccountRequestSubmission is the type for account request submissions.
+	TypeAccountRequestSubmission = "account_request_submissions"
+)
+
+// Terminal returns true, if the status is terminal and does not change anymore.
+func (s SubmissionStatusString) Terminal() bool {
+	return s == SubmissionDeliveryConfirmed || s == SubmissionDeliveryFailed
+}
+
+// AccountRequestsEnvelope is an envelope for a list of account requests.
+type AccountRequestsEnvelope struct {
+	Data  []*AccountRequest `json:"data"`
+	Links *Links            `json:"links,omitempty"`
+}
+
+// AccountRequestEnvelope is an envelope for a single account request.
+type AccountRequestEnvelope struct {
+	Data *AccountRequest `json:"data"`
+}
+
+// AccountRequest asks Form3 to open an account. The account is opened asynchronously, when the request is submitted.
+type AccountRequest struct {
+	Resource
+	// Attr are the attributes of the account to open.
+	Attr *AccountRequestAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the opened account and the submissions of the request, set server side.
+	Relationships *AccountRequestRelationships `json:"relationships,omitempty"`
+}
+
+// AccountRequestAttr are the account request specific attributes.
+type AccountRequestAttr struct {
+	Name          []string `json:"name,omitempty"`
+	AccountNumber string   `json:"account_number,omitempty"` // A unique account number will automatically be generated if not provided.
+	BankId        string   `json:"bank_id,omitempty"`
+	BankIdCode    string   `json:"bank_id_code,omitempty"`
+	BaseCurrency  string   `json:"base_currency,omitempty"`
+	Bic           string   `json:"bic,omitempty"`
+	Country       string   `json:"country,omitempty"`
+	CustomerId    string   `json:"customer_id,omitempty"`
+	Iban          string   `json:"iban,omitempty"` // Will be calculated from other fields if not supplied.
+
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+}
+
+// AccountRequestRelationships are the relationships of an account request.
+type AccountRequestRelationships struct {
+	Account                  *Relationship                  `json:"account,omitempty"`
+	MasterAccount            *Relationship                  `json:"master_account,omitempty"`
+	AccountRequestSubmission *AccountRequestSubmissionsData `json:"account_request_submission,omitempty"`
+}
+
+// AccountRequestSubmissionsData embeds the submissions of an account request into its relationships.
+type AccountRequestSubmissionsData struct {
+	Data []*AccountRequestSubmission `json:"data,omitempty"`
+}
+
+// AccountRequestSubmissionEnvelope is an envelope for a single account request submission.
+type AccountRequestSubmissionEnvelope struct {
+	Data *AccountRequestSubmission `json:"data"`
+}
+
+// AccountRequestSubmission submits an account request to Form3, its status tracks the opening of the account.
+type AccountRequestSubmission struct {
+	Resource
+	// Attr are the attributes of the submission, set server side.
+	Attr *AccountRequestSubmissionAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the account request and the opened account, set server side.
+	Relationships *AccountRequestSubmissionRelationships `json:"relationships,omitempty"`
+}
+
+// AccountRequestSubmissionAttr are the account request submission specific attributes.
+type AccountRequestSubmissionAttr struct {
+	Status             SubmissionStatusString `json:"status,omitempty"`
+	StatusReason       string                 `json:"status_reason,omitempty"`
+	SubmissionDateTime *time.Time             `json:"submission_datetime,omitempty"`
+}
+
+// AccountRequestSubmissionRelationships are the relationships of an account request submission.
+type AccountRequestSubmissionRelationships struct {
+	Account        *Relationship        `json:"account,omitempty"`
+	AccountRequest *AccountRequestsData `json:"account_request,omitempty"`
+}
+
+// AccountRequestsData embeds the account request into the relationships of a submission.
+type AccountRequestsData struct {
+	Data []*AccountRequest `json:"data,omitempty"`
+}
+
+// NewAccountRequest is a small helper method to create a basic structure for a new account request with a new id. The
+// created structure can be modified after creation or directly used to create a new request. If the organization-id
+// is nil, then the DefaultOrganizationId is used.
+func NewAccountRequest(organizationId *string, country string, bic string, baseCurrency string) *AccountRequest {
+	request := new(AccountRequest)
+	request.Type = TypeAccountRequest
+	request.Id = uuid.New().String()
+	if organizationId == nil {
+		organizationId = &DefaultOrganizationId
+	}
+	request.OrganisationId = *organizationId
+	request.Attr = &AccountRequestAttr{Country: country, Bic: bic, BaseCurrency: baseCurrency}
+	return request
+}
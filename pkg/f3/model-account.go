@@ -1,6 +1,7 @@
 package f3
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 )
 
@@ -26,7 +27,8 @@ const (
 
 // AccountsEnvelope is an envelope for a list of accounts.
 type AccountsEnvelope struct {
-	Data []*Account `json:"data"`
+	Data  []*Account `json:"data"`
+	Links *Links     `json:"links,omitempty"`
 }
 
 // AccountEnvelope is an envelope for a single account.
@@ -39,27 +41,108 @@ type Account struct {
 	Resource
 	// Attr are the attributes of the account.
 	Attr *AccountAttr `json:"attributes,omitempty"`
+
+	// Relationships refer to the events and the master account of the account, set server side.
+	Relationships *AccountRelationships `json:"relationships,omitempty"`
 }
 
-// AccountAttr are the account specific attributes.
+// AccountAttr are the account specific attributes. Attributes unknown to this client are kept in Unknown, so that
+// they survive a round trip from fetching an account to sending it back.
 type AccountAttr struct {
-	AlternativeNames        []string            `json:"alternative_names,omitempty"`
-	Name                    []string            `json:"name,omitempty"`
-	AccountClassification   string              `json:"account_classification,omitempty"`
-	AccountNumber           string              `json:"account_number,omitempty"` // A unique account number will automatically be generated if not provided. If provided, the account number is not validated.
-	BankId                  string              `json:"bank_id,omitempty"`
-	BankIdCode              string              `json:"bank_id_code,omitempty"`
-	BaseCurrency            string              `json:"base_currency,omitempty"`
-	CustomerId              *string             `json:"customer_id,omitempty"`
-	Bic                     string              `json:"bic,omitempty"`
-	Country                 string              `json:"country,omitempty"`
-	Iban                    string              `json:"iban,omitempty"` // Will be calculated from other fields if not supplied.
-	SecondaryIdentification string              `json:"secondary_identification,omitempty"`
-	Status                  AccountStatusString `json:"status,omitempty"`
-	StatusReason            *string             `json:"status_reason,omitempty"`
-	Switched                bool                `json:"switched,omitempty"`
-	AccountMatchingOptOut   bool                `json:"account_matching_opt_out,omitempty"`
-	JointAccount            bool                `json:"joint_account,omitempty"`
+	AlternativeNames            []string                    `json:"alternative_names,omitempty"`
+	AlternativeBankAccountNames []string                    `json:"alternative_bank_account_names,omitempty"` // Deprecated, use AlternativeNames.
+	Name                        []string                    `json:"name,omitempty"`
+	AccountClassification       string                      `json:"account_classification,omitempty"`
+	AccountNumber               string                      `json:"account_number,omitempty"`    // A unique account number will automatically be generated if not provided. If provided, the account number is not validated.
+	BankAccountName             string                      `json:"bank_account_name,omitempty"` // Deprecated, use Name.
+	BankId                      string                      `json:"bank_id,omitempty"`
+	BankIdCode                  string                      `json:"bank_id_code,omitempty"`
+	BaseCurrency                string                      `json:"base_currency,omitempty"`
+	CustomerId                  *string                     `json:"customer_id,omitempty"`
+	Bic                         string                      `json:"bic,omitempty"`
+	Country                     string                      `json:"country,omitempty"`
+	FirstName                   string                      `json:"first_name,omitempty"` // Deprecated, use Name.
+	Title                       string                      `json:"title,omitempty"`      // Deprecated, use Name.
+	Iban                        string                      `json:"iban,omitempty"`       // Will be calculated from other fields if not supplied.
+	SecondaryIdentification     string                      `json:"secondary_identification,omitempty"`
+	Status                      AccountStatusString         `json:"status,omitempty"`
+	StatusReason                *string                     `json:"status_reason,omitempty"`
+	Switched                    bool                        `json:"switched,omitempty"`
+	AccountMatchingOptOut       bool                        `json:"account_matching_opt_out,omitempty"`
+	JointAccount                bool                        `json:"joint_account,omitempty"`
+	ProcessingService           string                      `json:"processing_service,omitempty"`       // Deprecated.
+	UserDefinedInformation      string                      `json:"user_defined_information,omitempty"` // Deprecated, use UserDefinedData.
+	UserDefinedData             []UserDefinedData           `json:"user_defined_data,omitempty"`        // At most 5 pairs.
+	ValidationType              string                      `json:"validation_type,omitempty"`          // One of card, mandatory_reference or none.
+	ReferenceMask               string                      `json:"reference_mask,omitempty"`
+	AcceptanceQualifier         string                      `json:"acceptance_qualifier,omitempty"`
+	NameMatchingStatus          string                      `json:"name_matching_status,omitempty"` // One of supported, switched, opted_out or not_supported.
+	PrivateIdentification       *PrivateIdentification      `json:"private_identification,omitempty"`
+	OrganisationIdentification  *OrganisationIdentification `json:"organisation_identification,omitempty"`
+
+	// Unknown are the attributes received from the account API, which are unknown to this client, by name.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the attributes and keeps the unknown ones in Unknown.
+func (aa *AccountAttr) UnmarshalJSON(data []byte) error {
+	type plain AccountAttr
+	unknown, e := unmarshalKnown(data, (*plain)(aa))
+	aa.Unknown = unknown
+	return e
+}
+
+// MarshalJSON encodes the attributes including the unknown ones.
+func (aa AccountAttr) MarshalJSON() ([]byte, error) {
+	type plain AccountAttr
+	return marshalWithUnknown(plain(aa), aa.Unknown)
+}
+
+// UserDefinedData is a key-value pair added to each payment received to an account.
+type UserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PrivateIdentification identifies a private account holder.
+type PrivateIdentification struct {
+	Address                  []string `json:"address,omitempty"`
+	BirthCountry             string   `json:"birth_country,omitempty"`
+	BirthDate                string   `json:"birth_date,omitempty"` // Formatted as YYYY-MM-DD.
+	City                     string   `json:"city,omitempty"`
+	Country                  string   `json:"country,omitempty"`
+	Identification           string   `json:"identification,omitempty"`
+	IdentificationIssuer     string   `json:"identification_issuer,omitempty"`
+	IdentificationScheme     string   `json:"identification_scheme,omitempty"`
+	IdentificationSchemeCode string   `json:"identification_scheme_code,omitempty"`
+}
+
+// OrganisationIdentification identifies an organisation holding an account.
+type OrganisationIdentification struct {
+	Actors                   []*OrganisationIdentificationActor `json:"actors,omitempty"`
+	Address                  []string                           `json:"address,omitempty"`
+	City                     string                             `json:"city,omitempty"`
+	Country                  string                             `json:"country,omitempty"`
+	Identification           string                             `json:"identification,omitempty"`
+	IdentificationIssuer     string                             `json:"identification_issuer,omitempty"`
+	IdentificationScheme     string                             `json:"identification_scheme,omitempty"`
+	IdentificationSchemeCode string                             `json:"identification_scheme_code,omitempty"`
+	RegistrationNumber       string                             `json:"registration_number,omitempty"`
+	TaxResidency             string                             `json:"tax_residency,omitempty"`
+}
+
+// OrganisationIdentificationActor is a person acting for an organisation holding an account.
+type OrganisationIdentificationActor struct {
+	BirthDate string   `json:"birth_date,omitempty"` // Formatted as YYYY-MM-DD.
+	Name      []string `json:"name,omitempty"`       // Up to four lines.
+	Residency string   `json:"residency,omitempty"`
+	Role      string   `json:"role,omitempty"`
+}
+
+// AccountRelationships are the relationships of an account.
+type AccountRelationships struct {
+	AccountEvents *Relationship `json:"account_events,omitempty"`
+	MasterAccount *Relationship `json:"master_account,omitempty"`
 }
 
 // WithStatusPending set the status to StatusPending.
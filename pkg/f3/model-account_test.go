@@ -1,8 +1,10 @@
 package f3_test
 
 import (
+	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"reflect"
 	"testing"
 )
 
@@ -127,3 +129,29 @@ func TestAccountAttr_WithStatusPending(t *testing.T) {
 		t.Errorf("The account.Attr.StatusReason should have been nil, but was: %s", *attr.StatusReason)
 	}
 }
+
+func TestAccountAttr_JSON(t *testing.T) {
+	raw := `{"country":"GB","name_matching_status":"opted_out","user_defined_data":[{"key":"k","value":"v"}],` +
+		`"organisation_identification":{"actors":[{"name":["Jane"],"role":"director"}]},"future_field":[1,2]}`
+	var attr f3.AccountAttr
+	if e := json.Unmarshal([]byte(raw), &attr); e != nil {
+		t.Fatalf("Failed to decode the attributes: %s", e.Error())
+	}
+	if attr.NameMatchingStatus != "opted_out" || attr.UserDefinedData[0].Value != "v" ||
+		attr.OrganisationIdentification.Actors[0].Role != "director" {
+		t.Errorf("Failed to decode the known attributes: %+v", attr)
+	}
+	if len(attr.Unknown) != 1 || string(attr.Unknown["future_field"]) != "[1,2]" {
+		t.Errorf("Expected only future_field to be unknown, but got: %v", attr.Unknown)
+	}
+	encoded, e := json.Marshal(&attr)
+	if e != nil {
+		t.Fatalf("Failed to encode the attributes: %s", e.Error())
+	}
+	var before, after map[string]any
+	_ = json.Unmarshal([]byte(raw), &before)
+	_ = json.Unmarshal(encoded, &after)
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("Expected the round trip to preserve the attributes, but got: %s", encoded)
+	}
+}
@@ -1,6 +1,12 @@
 package f3
 
-import "time"
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
 
 // Resource is an abstract base structure with the shared attributes of all resources.
 type Resource struct {
@@ -25,3 +31,107 @@ type Resource struct {
 	// ModifiedOn is the time when the record was last modified, set server side.
 	ModifiedOn *time.Time `json:"modified_on,omitempty"`
 }
+
+// Links are the JSON:API links of a response. For lists, they link to the other pages of the list.
+type Links struct {
+	// Self is the link to the response itself.
+	Self string `json:"self,omitempty"`
+
+	// First is the link to the first page, if any.
+	First string `json:"first,omitempty"`
+
+	// Last is the link to the last page, if any.
+	Last string `json:"last,omitempty"`
+
+	// Next is the link to the next page, if any.
+	Next string `json:"next,omitempty"`
+
+	// Prev is the link to the previous page, if any.
+	Prev string `json:"prev,omitempty"`
+}
+
+// Relationship refers to related resources by their type and id.
+type Relationship struct {
+	Data []RelationshipData `json:"data,omitempty"`
+}
+
+// RelationshipData is the reference to a single related resource.
+type RelationshipData struct {
+	// Id is the unique identifier of the related resource.
+	Id string `json:"id,omitempty"`
+
+	// Type is the type of the related resource.
+	Type string `json:"type,omitempty"`
+}
+
+// knownFields caches the JSON names of the fields of a struct type by type.
+var knownFields sync.Map
+
+// jsonNames returns the JSON names of the fields of the given struct type.
+func jsonNames(t reflect.Type) map[string]bool {
+	if names, found := knownFields.Load(t); found {
+		return names.(map[string]bool)
+	}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			for embedded := range jsonNames(field.Type) {
+				names[embedded] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	knownFields.Store(t, names)
+	return names
+}
+
+// unmarshalKnown decodes the given JSON object into the given struct and returns the members of the object, which do
+// not match any field of the struct.
+func unmarshalKnown(data []byte, target any) (map[string]json.RawMessage, error) {
+	if e := json.Unmarshal(data, target); e != nil {
+		return nil, e
+	}
+	var members map[string]json.RawMessage
+	if json.Unmarshal(data, &members) != nil {
+		return nil, nil
+	}
+	known := jsonNames(reflect.TypeOf(target).Elem())
+	var unknown map[string]json.RawMessage
+	for name, value := range members {
+		if !known[name] {
+			if unknown == nil {
+				unknown = map[string]json.RawMessage{}
+			}
+			unknown[name] = value
+		}
+	}
+	return unknown, nil
+}
+
+// marshalWithUnknown encodes the given struct and adds the given unknown members to the JSON object. Fields of the
+// struct take precedence over unknown members with the same name.
+func marshalWithUnknown(source any, unknown map[string]json.RawMessage) ([]byte, error) {
+	data, e := json.Marshal(source)
+	if e != nil || len(unknown) == 0 {
+		return data, e
+	}
+	var members map[string]json.RawMessage
+	if e = json.Unmarshal(data, &members); e != nil {
+		return nil, e
+	}
+	for name, value := range unknown {
+		if _, exists := members[name]; !exists {
+			members[name] = value
+		}
+	}
+	return json.Marshal(members)
+}
@@ -0,0 +1,65 @@
+package f3
+
+import (
+	"context"
+	"time"
+)
+
+// operation describes a client operation, so that retries, the circuit breaker and other cross-cutting concerns can
+// treat them accordingly.
+type operation struct {
+	// name is the unique name of the operation.
+	name string
+
+	// idempotent is true, when the operation can be safely sent again.
+	idempotent bool
+
+	// request is the name of the swagger definition of the request body, if any.
+	request string
+
+	// response is the name of the swagger definition of the body of successful responses, if any.
+	response string
+}
+
+var (
+	opHealth        = operation{name: "health", idempotent: true, response: "Healthy"}
+	opCreateAccount = operation{name: "create_account", request: "AccountCreation", response: "AccountCreationResponse"}
+	opFetchAccount  = operation{name: "fetch_account", idempotent: true, response: "AccountDetailsResponse"}
+	opDeleteAccount = operation{name: "delete_account"}
+	opListAccounts  = operation{name: "list_accounts", idempotent: true, response: "AccountDetailsListResponse"}
+	opPatchAccount  = operation{name: "patch_account", response: "AccountDetailsResponse"} // The swagger declares no usable request definition.
+
+	opListAccountEvents = operation{name: "list_account_events", idempotent: true, response: "AccountEventListResponse"}
+
+	opListAccountIdentifications  = operation{name: "list_account_identifications", idempotent: true, response: "AccountIdentificationListResponse"}
+	opCreateAccountIdentification = operation{name: "create_account_identification", request: "AccountIdentificationRequest", response: "AccountIdentificationResponse"}
+	opFetchAccountIdentification  = operation{name: "fetch_account_identification", idempotent: true, response: "AccountIdentificationResponse"}
+	opPatchAccountIdentification  = operation{name: "patch_account_identification", request: "AccountIdentificationRequest", response: "AccountIdentificationResponse"}
+	opDeleteAccountIdentification = operation{name: "delete_account_identification"}
+
+	opListAccountRequests            = operation{name: "list_account_requests", idempotent: true, response: "AccountRequestListResponse"}
+	opCreateAccountRequest           = operation{name: "create_account_request", request: "AccountRequestCreation", response: "AccountRequestResponse"}
+	opFetchAccountRequest            = operation{name: "fetch_account_request", idempotent: true, response: "AccountRequestResponse"}
+	opCreateAccountRequestSubmission = operation{name: "create_account_request_submission", request: "AccountRequestSubmissionCreation", response: "AccountRequestSubmissionResponse"}
+	opFetchAccountRequestSubmission  = operation{name: "fetch_account_request_submission", idempotent: true, response: "AccountRequestSubmissionResponse"}
+
+	opListAccountAmendments            = operation{name: "list_account_amendments", idempotent: true, response: "AccountAmendmentListResponse"}
+	opCreateAccountAmendment           = operation{name: "create_account_amendment", request: "AccountAmendmentCreation", response: "AccountAmendmentResponse"}
+	opFetchAccountAmendment            = operation{name: "fetch_account_amendment", idempotent: true, response: "AccountAmendmentResponse"}
+	opCreateAccountAmendmentSubmission = operation{name: "create_account_amendment_submission", request: "AccountAmendmentSubmissionCreation", response: "AccountAmendmentSubmissionResponse"}
+	opFetchAccountAmendmentSubmission  = operation{name: "fetch_account_amendment_submission", idempotent: true, response: "AccountAmendmentSubmissionResponse"}
+)
+
+// begin is called at the start of every operation with attributes describing it as alternating key/value pairs. It
+// returns the context to execute the operation with and the function to call with the result, when the operation is
+// done.
+func (c *Client) begin(ctx context.Context, op operation, attributes ...any) (context.Context, func(er Err)) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, op, attributes...)
+	return ctx, func(er Err) {
+		if c.metrics != nil {
+			c.metrics.observe(op.name, time.Since(start), er)
+		}
+		endSpan(span, er)
+	}
+}
@@ -0,0 +1,63 @@
+package f3
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option is a functional option that configures a Client while being created by NewClient.
+type Option func(c *Client)
+
+// WithEndPoint binds the client to the given endpoint instead of the DefaultEndPoint.
+func WithEndPoint(endpoint string) Option {
+	return func(c *Client) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithTimeout sets the timeout for requests, including connection time, redirects and reading the response body,
+// instead of the DefaultTimeout. A timeout of zero means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithTransport sets the round-tripper to send the requests with instead of the shared DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+// WithHttpClient replaces the underlying http client. The client is used as given, so WithTimeout and WithTransport
+// applied afterwards will modify it.
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithUserAgent sets the value of the User-Agent header send with all requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithOrganisationId sets the organisation identifier to be used for accounts that are created without one, instead of
+// the DefaultOrganizationId.
+func WithOrganisationId(organisationId string) Option {
+	return func(c *Client) {
+		c.organisationId = organisationId
+	}
+}
+
+// WithLogger sets the logger to write log records to. By default, the client does not log.
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
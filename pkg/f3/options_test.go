@@ -0,0 +1,79 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewClient_WithOptions(t *testing.T) {
+	transport := &http.Transport{}
+	client := f3.NewClient(f3.WithTimeout(time.Second), f3.WithTransport(transport))
+	httpClient := client.HttpClient()
+	if httpClient.Transport != transport {
+		t.Errorf("The transport option was not applied")
+	}
+	if httpClient.Timeout != time.Second {
+		t.Errorf("The timeout option was not applied, expected %v, got %v", time.Second, httpClient.Timeout)
+	}
+	if f3.NewClient().HttpClient().Timeout != f3.DefaultTimeout {
+		t.Errorf("The options of one client must not modify other clients")
+	}
+	httpClient.Timeout = 2 * time.Second
+	if client.HttpClient().Timeout != 2*time.Second {
+		t.Errorf("Modifications of the http client must be applied to the client")
+	}
+}
+
+func TestNewClient_WithEndPointAndUserAgent(t *testing.T) {
+	var (
+		userAgent      string
+		organisationId string
+		records        int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		var envelope f3.AccountEnvelope
+		_ = json.NewDecoder(r.Body).Decode(&envelope)
+		if envelope.Data != nil {
+			organisationId = envelope.Data.OrganisationId
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&envelope)
+	}))
+	defer server.Close()
+	logger := f3.LoggerFunc(func(level f3.LogLevel, msg string, fields ...any) {
+		records++
+	})
+	client := f3.NewClient(
+		f3.WithEndPoint(server.URL),
+		f3.WithUserAgent("tests"),
+		f3.WithOrganisationId(f3.DefaultIntegrationOrganizationId),
+		f3.WithLogger(logger))
+
+	account := createTestAccount(false)
+	account.OrganisationId = ""
+	if _, e := client.CreateAccount(account); e != nil {
+		t.Fatalf("Failed to create account: %s", e.Error())
+	}
+	if userAgent != "tests" {
+		t.Errorf("Expected the user agent 'tests', but got '%s'", userAgent)
+	}
+	if organisationId != f3.DefaultIntegrationOrganizationId {
+		t.Errorf("Expected the organisation id '%s', but got '%s'", f3.DefaultIntegrationOrganizationId, organisationId)
+	}
+	if account.OrganisationId != "" {
+		t.Errorf("The given account must not be modified")
+	}
+
+	server.Close()
+	if _, e := client.CreateAccount(account); e == nil {
+		t.Fatalf("Created an account with a closed server")
+	}
+	if records == 0 {
+		t.Errorf("The failed request was not logged")
+	}
+}
@@ -0,0 +1,120 @@
+package f3
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redacted is the value that replaces redacted values.
+const redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the names of the JSON attributes whose values are redacted, before being logged. They
+// cover the personal data of the account holder, including the deprecated name attributes, the private identification
+// and the actors of organisations, and the account identifiers.
+var DefaultRedactedFields = []string{
+	"name", "alternative_names", "bank_account_name", "alternative_bank_account_names", "first_name", "title",
+	"private_identification", "birth_date", "residency", "iban", "account_number", "customer_id",
+}
+
+// WithRedactedFields replaces the DefaultRedactedFields by the given JSON attribute names. Calling it without any name
+// disables the redaction, which should only be done, when the logs do not leave the PCI/PII boundary.
+func WithRedactedFields(fields ...string) Option {
+	return func(c *Client) {
+		c.redactedFields = toSet(fields)
+	}
+}
+
+// toSet converts the given strings into a set.
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// redactor redacts the values of sensitive fields from JSON payloads and free text, like error messages.
+type redactor struct {
+	fields  map[string]bool
+	secrets []string
+}
+
+// newRedactor creates a redactor for the given fields. The values of these fields found in the given JSON payloads
+// are remembered as secrets, so that they can be removed from free text as well.
+func newRedactor(fields map[string]bool, payloads ...[]byte) *redactor {
+	r := &redactor{fields: fields}
+	for _, payload := range payloads {
+		var value any
+		if json.Unmarshal(payload, &value) == nil {
+			r.collect(value, false)
+		}
+	}
+	return r
+}
+
+// collect remembers all strings below sensitive fields as secrets.
+func (r *redactor) collect(value any, sensitive bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			r.collect(child, sensitive || r.fields[key])
+		}
+	case []any:
+		for _, child := range v {
+			r.collect(child, sensitive)
+		}
+	case string:
+		if sensitive && len(v) > 0 {
+			r.secrets = append(r.secrets, v)
+		}
+	}
+}
+
+// payload returns the given JSON payload with the values of all sensitive fields redacted. Payloads that are no valid
+// JSON are completely redacted, when there are sensitive fields, because they can't be inspected.
+func (r *redactor) payload(raw []byte) string {
+	if len(r.fields) == 0 {
+		return string(raw)
+	}
+	var value any
+	if json.Unmarshal(raw, &value) != nil {
+		if len(raw) == 0 {
+			return ""
+		}
+		return redacted
+	}
+	redactedRaw, e := json.Marshal(r.redact(value))
+	if e != nil {
+		return redacted
+	}
+	return string(redactedRaw)
+}
+
+// redact replaces the values of all sensitive fields and all known secrets in other strings.
+func (r *redactor) redact(value any) any {
+	switch v := value.(type) {
+	case string:
+		return r.text(v)
+	case map[string]any:
+		for key, child := range v {
+			if r.fields[key] {
+				v[key] = redacted
+			} else {
+				v[key] = r.redact(child)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = r.redact(child)
+		}
+	}
+	return value
+}
+
+// text returns the given text with all known secrets replaced.
+func (r *redactor) text(text string) string {
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+	return text
+}
@@ -2,5 +2,6 @@ package f3
 
 // ErrorResponse is the response the account API sends back, when an error encountered.
 type ErrorResponse struct {
+	ErrorCode    string `json:"error_code,omitempty"`
 	ErrorMessage string `json:"error_message"`
 }
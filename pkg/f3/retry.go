@@ -0,0 +1,203 @@
+package f3
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const headerRetryAfter = "Retry-After"
+
+// errNoGetBody is returned, when a request with body should be retried, but the body can't be recreated.
+var errNoGetBody = errors.New("the body of the request can not be recreated")
+
+// RetryPolicy configures how often and when requests are retried. Requests are retried, when sending them failed or
+// the server responded with 429 (Too Many Requests) or any 5xx status code. Only safe operations, like fetching an
+// account or the health check, and provably idempotent operations, like creating an account with a caller-supplied
+// id, are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximal amount of attempts, including the first one. A value less than two disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the time to wait before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the upper limit for the time to wait between two attempts.
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the backoff grows with every retry; values less than one are treated as one.
+	Multiplier float64
+
+	// Jitter is the fraction of the backoff that is randomized, between 0 (no jitter) and 1 (full jitter), so that
+	// concurrent clients do not retry in lockstep.
+	Jitter float64
+
+	// MaxRetryAfter is the upper limit for the time to wait, when the server sends a Retry-After header. When the
+	// server asks to wait longer, the request is not retried and the response is returned as is.
+	MaxRetryAfter time.Duration
+}
+
+var (
+	// DefaultRetryPolicy is the recommended retry policy, clients created by NewClient only use it, when enabled with
+	// WithRetryPolicy(DefaultRetryPolicy).
+	DefaultRetryPolicy = RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxRetryAfter:  10 * time.Second,
+	}
+
+	// NoRetry is a retry policy that disables retries; it is used by clients created by NewClient.
+	NoRetry = RetryPolicy{MaxAttempts: 1}
+)
+
+// WithRetryPolicy sets the retry policy to use instead of NoRetry, like DefaultRetryPolicy. By default, requests are
+// sent only once.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// backoff returns the time to wait before the given retry, starting with 1 for the first retry.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || backoff < float64(p.MaxBackoff)); i++ {
+		backoff *= multiplier
+	}
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		backoff -= backoff * jitter * rand.Float64()
+	}
+	return time.Duration(backoff)
+}
+
+// retryAfter returns the time the server asks to wait before retrying, taken from the Retry-After header, which is
+// either given in seconds or as HTTP date. If the header is missing or invalid, false is returned.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get(headerRetryAfter)
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, e := strconv.Atoi(value); e == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, e := http.ParseTime(value); e == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isRetryableStatus tests if the status code signals a temporary problem of the server.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// send sends the given request for the given operation and returns the response and the number of attempts made. If
+// the operation is idempotent, the request is retried according to the retry policy of the client, when sending fails
+// or the server responds with a temporary error. If the context of the request is done while waiting for the next
+// attempt, the error of the context is returned. If the circuit breaker of the client rejects an attempt,
+// errCircuitOpen is returned, if the client side limits are exhausted and the client should fail fast, errThrottled.
+func (c *Client) send(op operation, req *http.Request) (*http.Response, int, error) {
+	policy := &c.retryPolicy
+	ctx := context.WithValue(req.Context(), operationKey{}, op.name)
+	req = req.WithContext(ctx)
+	attempt := 1
+	for {
+		if !c.allow(ctx, op) {
+			c.log(LogWarn, "Circuit open, request rejected", "method", req.Method, "path", req.URL.Path, "attempt", attempt)
+			return nil, attempt, errCircuitOpen
+		}
+		release, limitErr := c.acquire(ctx)
+		if limitErr != nil {
+			c.log(LogWarn, "Request throttled", "method", req.Method, "path", req.URL.Path, "attempt", attempt)
+			return nil, attempt, limitErr
+		}
+		resp, e := c.do(req, attempt)
+		if e == nil && resp != nil && resp.Body != nil {
+			resp.Body = releasingBody{resp.Body, release}
+		} else {
+			discard(resp)
+			release()
+		}
+		c.record(ctx, op, resp, e)
+		if !op.idempotent || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return resp, attempt, e
+		}
+		if e == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, attempt, e
+		}
+		wait := policy.backoff(attempt)
+		if e == nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > policy.MaxRetryAfter {
+					return resp, attempt, e
+				}
+				wait = after
+			}
+			c.log(LogInfo, "Retry request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode,
+				"attempt", attempt, "wait", wait)
+		} else {
+			c.log(LogInfo, "Retry request", "method", req.Method, "path", req.URL.Path, "error", errorText(e),
+				"attempt", attempt, "wait", wait)
+		}
+		next, cloneErr := cloneRequest(ctx, req)
+		if cloneErr != nil {
+			return resp, attempt, e
+		}
+		discard(resp)
+		if waitErr := sleep(ctx, wait); waitErr != nil {
+			return nil, attempt, waitErr
+		}
+		req = next
+		attempt++
+	}
+}
+
+// cloneRequest creates a copy of the request to send it again, including a fresh copy of the body.
+func cloneRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	clone := req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, errNoGetBody
+		}
+		body, e := req.GetBody()
+		if e != nil {
+			return nil, e
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// sleep waits for the given duration or until the context is done. In the latter case the error of the context is
+// returned.
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
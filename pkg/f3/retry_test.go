@@ -0,0 +1,136 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetryPolicy = f3.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Multiplier:     2,
+	Jitter:         0.5,
+	MaxRetryAfter:  time.Second,
+}
+
+func TestClient_FetchAccount_Retry(t *testing.T) {
+	var calls, failures int32 = 0, 2
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= atomic.LoadInt32(&failures) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(fastRetryPolicy))
+
+	fetched, e := client.FetchAccount(account.Id)
+	if e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	if fetched == nil || fetched.Id != account.Id {
+		t.Errorf("Fetched the wrong account: %v", fetched)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, but made %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&failures, 3)
+	_, e = client.FetchAccount(account.Id)
+	if e == nil {
+		t.Fatalf("Fetched an account, even while all attempts failed")
+	}
+	if e.ErrorCode() != f3.ErrServer {
+		t.Errorf("Invalid error, expected %d, got %d", f3.ErrServer, e.ErrorCode())
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, but made %d", calls)
+	}
+}
+
+func TestClient_NoRetryByDefault(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	if _, e := client.FetchAccount(f3.IntegrationTestAccountId); e == nil || e.ErrorCode() != f3.ErrServer {
+		t.Fatalf("Expected a server error, got: %v", e)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt without a retry policy, but made %d", calls)
+	}
+}
+
+func TestClient_DeleteAccount_NoRetry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(fastRetryPolicy))
+
+	if e := client.DeleteAccount(f3.IntegrationTestAccountId, 0); e == nil {
+		t.Fatalf("Deleted an account, even while the server failed")
+	}
+	if calls != 1 {
+		t.Errorf("Delete must not be retried, but made %d attempts", calls)
+	}
+}
+
+func TestClient_CreateAccount_RetryConflict(t *testing.T) {
+	var posts int32
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			// The first attempt creates the account, but the response is lost.
+			if atomic.AddInt32(&posts, 1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+			} else {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error_message":"Account cannot be created as it violates a duplicate constraint"}`))
+			}
+		case http.MethodGet:
+			version := uint64(0)
+			created := *account
+			created.Version = &version
+			_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: &created})
+		}
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithRetryPolicy(fastRetryPolicy))
+
+	created, e := client.CreateAccount(account)
+	if e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+	if created == nil || created.Version == nil {
+		t.Fatalf("Expected the created account to be returned, got: %v", created)
+	}
+
+	other := createTestAccount(true)
+	other.Attr.Country = "DE"
+	atomic.StoreInt32(&posts, 0)
+	created, e = client.CreateAccount(other)
+	if created != nil {
+		t.Errorf("Returned a different account as being created")
+	}
+	if e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict, got: %v", e)
+	}
+}
@@ -0,0 +1,94 @@
+package f3
+
+import (
+	"fmt"
+	"strings"
+)
+
+// definitionApiError is the definition of the body of error responses.
+const definitionApiError = "ApiError"
+
+// ValidationMode selects how the client treats payloads violating the schema of the account API, see
+// WithSchemaValidation.
+type ValidationMode int
+
+const (
+	// ValidationLog logs violations with LogWarn and processes the payloads anyway.
+	ValidationLog ValidationMode = iota
+
+	// ValidationStrict rejects requests violating the schema with ErrBadRequest, before they are sent, and responses
+	// violating the schema with ErrResponse. The cause is the SchemaViolations. Violations of error responses are only
+	// logged, so that the error status is not hidden.
+	ValidationStrict
+)
+
+// SchemaValidator validates JSON documents against the definitions of the account API. The package f3schema provides
+// an implementation based on the swagger specification of the account API.
+type SchemaValidator interface {
+	// Validate validates the given JSON document against the definition with the given name and returns all
+	// violations found.
+	Validate(definition string, document []byte) []Violation
+}
+
+// Violation is a value of a JSON document violating the schema.
+type Violation struct {
+	// Pointer is the JSON pointer (RFC 6901) to the offending value, like "/data/attributes/country"; empty for the
+	// whole document.
+	Pointer string
+
+	// Message describes the violation.
+	Message string
+}
+
+func (v Violation) String() string {
+	if len(v.Pointer) == 0 {
+		return "/: " + v.Message
+	}
+	return v.Pointer + ": " + v.Message
+}
+
+// SchemaViolations is the cause of the errors returned with ValidationStrict for payloads violating the schema.
+type SchemaViolations struct {
+	// Definition is the name of the definition the payload was validated against.
+	Definition string
+
+	// Violations are the violations found.
+	Violations []Violation
+}
+
+func (s *SchemaViolations) Error() string {
+	violations := make([]string, len(s.Violations))
+	for i, violation := range s.Violations {
+		violations[i] = violation.String()
+	}
+	return fmt.Sprintf("Payload violates %s: %s", s.Definition, strings.Join(violations, "; "))
+}
+
+// WithSchemaValidation validates all payloads sent to and received from the account API with the given validator.
+// Violations are logged or, with ValidationStrict, returned as errors. The validation is meant for debug and staging
+// builds, it is disabled by default.
+func WithSchemaValidation(validator SchemaValidator, mode ValidationMode) Option {
+	return func(c *Client) {
+		c.validator = validator
+		c.validationMode = mode
+	}
+}
+
+// validate validates the given payload against the given definition, logs the violations and returns them, if the
+// payload should be rejected.
+func (c *Client) validate(op operation, definition string, payload string, document []byte) *SchemaViolations {
+	if c.validator == nil || len(definition) == 0 || len(document) == 0 {
+		return nil
+	}
+	violations := c.validator.Validate(definition, document)
+	if len(violations) == 0 {
+		return nil
+	}
+	result := &SchemaViolations{Definition: definition, Violations: violations}
+	c.log(LogWarn, "Schema violation", "operation", op.name, "payload", payload, "definition", definition,
+		"violations", result.Error())
+	if c.validationMode != ValidationStrict {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,113 @@
+package f3_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// rejectingValidator reports a violation for every document of the rejected definitions and records all validations.
+type rejectingValidator struct {
+	mutex     sync.Mutex
+	rejected  map[string]bool
+	validated []string
+}
+
+func (v *rejectingValidator) Validate(definition string, document []byte) []f3.Violation {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.validated = append(v.validated, definition)
+	if !json.Valid(document) {
+		return []f3.Violation{{Message: "invalid JSON"}}
+	}
+	if v.rejected[definition] {
+		return []f3.Violation{{Pointer: "/data/attributes/country", Message: "must match the pattern ^[A-Z]{2}$"}}
+	}
+	return nil
+}
+
+func newSchemaClient(server *f3test.Server, validator f3.SchemaValidator, mode f3.ValidationMode, logger f3.Logger) *f3.Client {
+	return f3.NewClient(f3.WithEndPoint(server.EndPoint()), f3.WithSchemaValidation(validator, mode),
+		f3.WithLogger(logger), f3.WithRetryPolicy(f3.NoRetry))
+}
+
+func TestClient_SchemaValidation(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	validator := &rejectingValidator{}
+	client := newSchemaClient(server, validator, f3.ValidationStrict, nil)
+
+	account := createTestAccount(true)
+	if _, e := client.CreateAccount(account); e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	if e := client.DeleteAccount(account.Id, 1); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Fatalf("Expected a conflict, got: %v", e)
+	}
+	expected := []string{"AccountCreation", "AccountCreationResponse", "AccountDetailsResponse", "ApiError"}
+	if fmt.Sprint(validator.validated) != fmt.Sprint(expected) {
+		t.Errorf("Expected the definitions %v to be validated, got %v", expected, validator.validated)
+	}
+}
+
+func TestClient_SchemaValidationStrict(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	validator := &rejectingValidator{rejected: map[string]bool{"AccountCreation": true}}
+	client := newSchemaClient(server, validator, f3.ValidationStrict, nil)
+
+	account := createTestAccount(true)
+	_, e := client.CreateAccount(account)
+	if e == nil || !errors.Is(e, f3.BadRequestError) {
+		t.Fatalf("Expected the invalid request to be rejected, got: %v", e)
+	}
+	var violations *f3.SchemaViolations
+	if !errors.As(e, &violations) || violations.Definition != "AccountCreation" ||
+		violations.Violations[0].Pointer != "/data/attributes/country" {
+		t.Errorf("Expected the violations as cause, got: %v", e.Unwrap())
+	}
+	if len(server.Accounts()) > 0 {
+		t.Errorf("Expected the invalid request not to be sent")
+	}
+
+	server.Put(account)
+	validator.rejected = map[string]bool{"AccountDetailsResponse": true, "ApiError": true}
+	_, e = client.FetchAccount(account.Id)
+	if e == nil || e.ErrorCode() != f3.ErrResponse || !errors.As(e, &violations) {
+		t.Fatalf("Expected the invalid response to be rejected, got: %v", e)
+	}
+	if !strings.Contains(e.Unwrap().Error(), "/data/attributes/country: must match the pattern") {
+		t.Errorf("Expected the pointer in the message, got: %s", e.Unwrap().Error())
+	}
+	if _, e = client.FetchAccount("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected invalid error responses not to hide the status, got: %v", e)
+	}
+}
+
+func TestClient_SchemaValidationLog(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	validator := &rejectingValidator{rejected: map[string]bool{"AccountCreation": true, "AccountCreationResponse": true}}
+	var logged []string
+	logger := f3.LoggerFunc(func(level f3.LogLevel, msg string, fields ...any) {
+		if msg == "Schema violation" && level == f3.LogWarn {
+			logged = append(logged, fmt.Sprint(fields...))
+		}
+	})
+	client := newSchemaClient(server, validator, f3.ValidationLog, logger)
+
+	if _, e := client.CreateAccount(createTestAccount(true)); e != nil {
+		t.Fatalf("Expected violations only to be logged, got: %s", e.Error())
+	}
+	if len(logged) != 2 || !strings.Contains(logged[0], "AccountCreation") || !strings.Contains(logged[1], "response") {
+		t.Errorf("Expected the violations of the request and the response to be logged, got: %q", logged)
+	}
+}
@@ -13,6 +13,10 @@ var (
 	// DefaultTimeout is the default timeout to be used for the http client.
 	DefaultTimeout = time.Second * 5
 
+	// DefaultUpdateAttempts is the default maximal amount of attempts of Client.UpdateAccount and
+	// Client.DeleteAccountLatest.
+	DefaultUpdateAttempts = 5
+
 	// DefaultOrganizationId is the default organization identifier to be used, when a new account is created. The variable
 	// is initialized from the environment variable F3_CLIENT_ORG_ID
 	DefaultOrganizationId string
@@ -0,0 +1,128 @@
+package f3_test
+
+import (
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// resourceType is the type of the embedded Resource of all resources.
+var resourceType = reflect.TypeOf(f3.Resource{})
+
+// collectResources adds the given type and all resource types reachable from it by name.
+func collectResources(t reflect.Type, resources map[string]reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == resourceType {
+			resources[t.Name()] = t
+		}
+		collectResources(field.Type, resources, seen)
+	}
+}
+
+// clientResources returns all resource types sent or received by the methods of the client, so that new resources
+// are checked against the swagger, when the client is extended.
+func clientResources() map[string]reflect.Type {
+	resources := map[string]reflect.Type{}
+	seen := map[reflect.Type]bool{}
+	client := reflect.TypeOf(&f3.Client{})
+	for i := 0; i < client.NumMethod(); i++ {
+		method := client.Method(i).Type
+		for j := 0; j < method.NumIn(); j++ {
+			collectResources(method.In(j), resources, seen)
+		}
+		for j := 0; j < method.NumOut(); j++ {
+			collectResources(method.Out(j), resources, seen)
+		}
+	}
+	return resources
+}
+
+func TestSwagger_Conformance(t *testing.T) {
+	swagger, e := f3test.LoadSwagger("../../api/form3-swagger.yaml")
+	if e != nil {
+		t.Fatalf("Failed to load the swagger: %s", e)
+	}
+	enums, e := f3test.ParseEnums(".")
+	if e != nil {
+		t.Fatalf("Failed to parse the enums: %s", e)
+	}
+	ignore := []string{
+		// The account API sets the timestamps of accounts and events, the swagger does not define them.
+		"*.created_on", "*.modified_on",
+		// Requests and amendments share the organisation identification of accounts, which has more fields.
+		"*.organisation_identification.identification_*", "*.organisation_identification.registration_number",
+		// The swagger reuses the definition of amendments for the body of account patches.
+		"AccountAmendment*.data",
+		// Events share the status of accounts, which includes closed, and the envelope of the related account.
+		"AccountEvent.attributes.status", "AccountEvent.attributes.description", "AccountEvent.relationships.account.links",
+		// The swagger defines the relationships of identifications, but does not refer to them.
+		"AccountIdentification.relationships",
+	}
+	conformance := &f3test.Conformance{Swagger: swagger, Enums: enums, Ignore: ignore}
+
+	contracts := map[string]any{
+		"Healthy":  f3.HealthyResponse{},
+		"ApiError": f3.ErrorResponse{},
+	}
+	resources := clientResources()
+	for name, resource := range resources {
+		contracts[name] = reflect.New(resource).Elem().Interface()
+	}
+	if len(resources) < 7 {
+		t.Errorf("Expected at least 7 resource types, found: %v", resources)
+	}
+	// Resources are received as read shape and sent in the body of requests as write shape, which may differ.
+	requests := map[string]string{}
+	names := make([]string, 0, len(contracts))
+	for name := range contracts {
+		if request, found := requestDefinition(swagger, name); found {
+			requests[name] = request
+		}
+		names = append(names, name)
+	}
+	if len(requests) < 5 {
+		t.Errorf("Expected at least 5 request definitions, found: %v", requests)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			for _, drift := range conformance.Check(name, contracts[name]) {
+				t.Error(drift)
+			}
+			if request, found := requests[name]; found {
+				for _, drift := range conformance.CheckRequest(request, contracts[name]) {
+					t.Error(drift)
+				}
+			}
+		})
+	}
+}
+
+// requestEnvelopes are the suffixes of the swagger definitions of request bodies, which wrap a resource in data.
+var requestEnvelopes = []string{"Creation", "Request"}
+
+// requestDefinition returns the name of the swagger definition of the resource with the given name, when sent in the
+// body of a request, like "AccountRequestCreate" for "AccountRequest", which the client encodes.
+func requestDefinition(swagger *f3test.Swagger, resource string) (string, bool) {
+	for _, suffix := range requestEnvelopes {
+		envelope, found := swagger.Definitions[resource+suffix]
+		if !found {
+			continue
+		}
+		if data, found := envelope.Properties["data"]; found && strings.HasPrefix(data.Ref, "#/definitions/") {
+			return strings.TrimPrefix(data.Ref, "#/definitions/"), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,103 @@
+package f3
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+)
+
+const headerTraceParent = "traceparent"
+
+// Tracer is a minimal interface to connect the client to a distributed tracing system. The client starts a span for
+// every operation and propagates it using the W3C traceparent header.
+type Tracer interface {
+	// Start starts a new span for the given operation as child of the span in the given context, if any, and returns
+	// the context holding the new span.
+	Start(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is a single span started by a Tracer.
+type Span interface {
+	// SetAttribute sets the attribute with the given key, like "f3.account_id" or "http.status_code".
+	SetAttribute(key string, value any)
+
+	// TraceParent returns the value of the W3C traceparent header to propagate the span, or an empty string, if the
+	// span should not be propagated.
+	TraceParent() string
+
+	// End ends the span.
+	End()
+}
+
+// WithTracer makes the client start a span for every operation using the given tracer. By default, the client does not
+// trace.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// FormatTraceParent formats the W3C traceparent header value for the given trace and parent span identifier.
+func FormatTraceParent(traceId [16]byte, spanId [8]byte, sampled bool) string {
+	flags := 0
+	if sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(traceId[:]), hex.EncodeToString(spanId[:]), flags)
+}
+
+// spanKey is the context key to store the span of an operation.
+type spanKey struct{}
+
+// traceParentKey is the context key to store a traceparent header value.
+type traceParentKey struct{}
+
+// ContextWithTraceParent returns a context holding the given W3C traceparent header value, for example taken from an
+// incoming request. The value is sent with all requests using the context, unless the client has a tracer, in which
+// case the span of the tracer is propagated.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+// traceParent returns the traceparent header value to send with requests using the given context.
+func traceParent(ctx context.Context) string {
+	if span := spanFromContext(ctx); span != nil {
+		return span.TraceParent()
+	}
+	value, _ := ctx.Value(traceParentKey{}).(string)
+	return value
+}
+
+// spanFromContext returns the span of the operation the context belongs to or nil.
+func spanFromContext(ctx context.Context) Span {
+	span, _ := ctx.Value(spanKey{}).(Span)
+	return span
+}
+
+// startSpan starts the span for the given operation, if the client has a tracer, and sets the given attributes, which
+// are alternating key/value pairs.
+func (c *Client) startSpan(ctx context.Context, op operation, attributes ...any) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := c.tracer.Start(ctx, "f3."+op.name)
+	if span == nil {
+		return ctx, nil
+	}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		span.SetAttribute(fmt.Sprintf("f3.%v", attributes[i]), attributes[i+1])
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// endSpan records the outcome of the operation and ends the span.
+func endSpan(span Span, er Err) {
+	if span == nil {
+		return
+	}
+	if er != nil {
+		span.SetAttribute("f3.error_code", er.ErrorCode())
+		span.SetAttribute("error", true)
+	}
+	span.End()
+}
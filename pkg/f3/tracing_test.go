@@ -0,0 +1,101 @@
+package f3_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+type testSpan struct {
+	operation  string
+	attributes map[string]any
+	ended      bool
+}
+
+func (t *testTracer) Start(ctx context.Context, operation string) (context.Context, f3.Span) {
+	span := &testSpan{operation: operation, attributes: map[string]any{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *testSpan) SetAttribute(key string, value any) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) TraceParent() string {
+	return f3.FormatTraceParent(
+		[16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		[8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		true)
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+func TestClient_WithTracer(t *testing.T) {
+	var traceParents []string
+	account := createTestAccount(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&f3.AccountEnvelope{Data: account})
+	}))
+	defer server.Close()
+	tracer := &testTracer{}
+	client := f3.NewClient(f3.WithEndPoint(server.URL), f3.WithTracer(tracer))
+
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	if e := client.DeleteAccount(account.Id, 7); e == nil {
+		t.Fatalf("Expected the delete to fail")
+	}
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+	fetch, del := tracer.spans[0], tracer.spans[1]
+	if fetch.operation != "f3.fetch_account" || !fetch.ended {
+		t.Errorf("Unexpected fetch span: %+v", fetch)
+	}
+	if fetch.attributes["f3.account_id"] != account.Id || fetch.attributes["http.status_code"] != 200 {
+		t.Errorf("Unexpected fetch span attributes: %v", fetch.attributes)
+	}
+	if del.attributes["f3.error_code"] != f3.ErrConflict || del.attributes["http.status_code"] != 409 {
+		t.Errorf("Unexpected delete span attributes: %v", del.attributes)
+	}
+	expected := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	for _, traceParent := range traceParents {
+		if traceParent != expected {
+			t.Errorf("Expected the traceparent %s, but got %s", expected, traceParent)
+		}
+	}
+}
+
+func TestClient_ContextWithTraceParent(t *testing.T) {
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		_, _ = w.Write([]byte(`{"status":"up"}`))
+	}))
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.URL))
+
+	expected := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+	if !client.IsHealthyWithContext(f3.ContextWithTraceParent(context.Background(), expected)) {
+		t.Fatalf("Expected the service to be healthy")
+	}
+	if traceParent != expected {
+		t.Errorf("Expected the traceparent %s, but got %s", expected, traceParent)
+	}
+}
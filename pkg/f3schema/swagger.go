@@ -0,0 +1,70 @@
+// Package f3schema validates the payloads exchanged with the Form3 account API against the definitions of its swagger
+// specification. It is meant for debug and staging builds, to detect bugs of the client and changes of the account API
+// before production:
+//
+//	validator, err := f3schema.Load("api/form3-swagger.yaml")
+//	...
+//	client := f3.NewClient(f3.WithSchemaValidation(validator, f3.ValidationStrict))
+package f3schema
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"strings"
+)
+
+// definitionPrefix is the prefix of references to definitions.
+const definitionPrefix = "#/definitions/"
+
+// Swagger is the part of a swagger 2.0 specification needed to validate payloads.
+type Swagger struct {
+	Definitions map[string]*Schema `yaml:"definitions"`
+}
+
+// Schema is a swagger schema of a definition or property.
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Enum       []any              `yaml:"enum"`
+	Required   []string           `yaml:"required"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Items      *Schema            `yaml:"items"`
+	AllOf      []*Schema          `yaml:"allOf"`
+	Pattern    string             `yaml:"pattern"`
+	MinLength  *int               `yaml:"minLength"`
+	MaxLength  *int               `yaml:"maxLength"`
+	MinItems   *int               `yaml:"minItems"`
+	MaxItems   *int               `yaml:"maxItems"`
+	Minimum    *float64           `yaml:"minimum"`
+	Maximum    *float64           `yaml:"maximum"`
+	Nullable   bool               `yaml:"x-nullable"`
+	ReadOnly   bool               `yaml:"readOnly"`
+	OmitEmpty  *bool              `yaml:"x-omitempty"`
+}
+
+// LoadSwagger reads the swagger specification from the given YAML file.
+func LoadSwagger(path string) (*Swagger, error) {
+	raw, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+	var swagger Swagger
+	if e = yaml.Unmarshal(raw, &swagger); e != nil {
+		return nil, fmt.Errorf("invalid swagger %s: %w", path, e)
+	}
+	return &swagger, nil
+}
+
+// Resolve follows the references of the given schema and returns the referenced schema. If a reference can't be
+// resolved, nil is returned.
+func (s *Swagger) Resolve(schema *Schema) *Schema {
+	for i := 0; schema != nil && len(schema.Ref) > 0; i++ {
+		if i > len(s.Definitions) || !strings.HasPrefix(schema.Ref, definitionPrefix) {
+			return nil
+		}
+		schema = s.Definitions[strings.TrimPrefix(schema.Ref, definitionPrefix)]
+	}
+	return schema
+}
@@ -0,0 +1,214 @@
+package f3schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	// formats validates the supported formats of strings.
+	formats = map[string]func(string) bool{
+		"uuid": uuidPattern.MatchString,
+		"date-time": func(value string) bool {
+			_, e := time.Parse(time.RFC3339Nano, value)
+			return e == nil
+		},
+		"date": func(value string) bool {
+			_, e := time.Parse("2006-01-02", value)
+			return e == nil
+		},
+	}
+)
+
+// Validator validates JSON documents against the definitions of a swagger specification, it implements
+// f3.SchemaValidator. The messages of the violations never contain the offending values, because they may contain
+// personal data.
+type Validator struct {
+	swagger  *Swagger
+	patterns sync.Map
+}
+
+// Load returns a validator for the swagger specification in the given YAML file.
+func Load(path string) (*Validator, error) {
+	swagger, e := LoadSwagger(path)
+	if e != nil {
+		return nil, e
+	}
+	return New(swagger), nil
+}
+
+// New returns a validator for the given swagger specification.
+func New(swagger *Swagger) *Validator {
+	return &Validator{swagger: swagger}
+}
+
+// Validate validates the given JSON document against the definition with the given name and returns all violations
+// ordered by their JSON pointer. Properties not defined by the swagger are accepted.
+func (v *Validator) Validate(definition string, document []byte) []f3.Violation {
+	schema, found := v.swagger.Definitions[definition]
+	if !found {
+		return []f3.Violation{{Message: fmt.Sprintf("definition %s not found in the swagger", definition)}}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value any
+	if e := decoder.Decode(&value); e != nil {
+		return []f3.Violation{{Message: "invalid JSON: " + e.Error()}}
+	}
+	var violations []f3.Violation
+	v.validate("", value, schema, &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+	return violations
+}
+
+// validate validates the value at the given JSON pointer against the given schema.
+func (v *Validator) validate(pointer string, value any, schema *Schema, violations *[]f3.Violation) {
+	schema = v.swagger.Resolve(schema)
+	if schema == nil {
+		*violations = append(*violations, f3.Violation{Pointer: pointer, Message: "unresolvable reference"})
+		return
+	}
+	report := func(format string, args ...any) {
+		*violations = append(*violations, f3.Violation{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, part := range schema.AllOf {
+		v.validate(pointer, value, part, violations)
+	}
+	if value == nil {
+		if !schema.Nullable && (len(schema.Type) > 0 || len(schema.Properties) > 0) {
+			report("must not be null")
+		}
+		return
+	}
+	schemaType := schema.Type
+	if len(schemaType) == 0 && len(schema.Properties) > 0 {
+		schemaType = "object"
+	}
+	switch schemaType {
+	case "string":
+		if text, ok := value.(string); !ok {
+			report("must be a string")
+		} else {
+			v.validateString(text, schema, report)
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			report("must be a %s", schemaType)
+			return
+		}
+		if _, e := strconv.ParseInt(string(number), 10, 64); e != nil && schemaType == "integer" {
+			report("must be an integer")
+			return
+		}
+		f, _ := number.Float64()
+		if schema.Minimum != nil && f < *schema.Minimum {
+			report("must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			report("must be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("must be a boolean")
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			report("must be an array")
+			return
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			report("must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			report("must have at most %d items", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				v.validate(pointer+"/"+strconv.Itoa(i), item, schema.Items, violations)
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			report("must be an object")
+			return
+		}
+		for _, name := range schema.Required {
+			if _, found := object[name]; !found {
+				*violations = append(*violations, f3.Violation{Pointer: pointer + "/" + escape(name), Message: "is required"})
+			}
+		}
+		for name, property := range schema.Properties {
+			if propertyValue, found := object[name]; found {
+				v.validate(pointer+"/"+escape(name), propertyValue, property, violations)
+			}
+		}
+	}
+	if len(schema.Enum) > 0 && !isEnumValue(value, schema.Enum) {
+		report("must be one of %v", schema.Enum)
+	}
+}
+
+// validateString validates the given string against the length, format and pattern of the given schema.
+func (v *Validator) validateString(text string, schema *Schema, report func(format string, args ...any)) {
+	length := utf8.RuneCountInString(text)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		report("must have at least %d characters", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		report("must have at most %d characters", *schema.MaxLength)
+	}
+	if valid, found := formats[schema.Format]; found && !valid(text) {
+		report("must be a valid %s", schema.Format)
+	}
+	if pattern := v.pattern(schema.Pattern); pattern != nil && !pattern.MatchString(text) {
+		report("must match the pattern %s", schema.Pattern)
+	}
+}
+
+// pattern returns the compiled pattern or nil, if there is no pattern or it can't be compiled.
+func (v *Validator) pattern(pattern string) *regexp.Regexp {
+	if len(pattern) == 0 {
+		return nil
+	}
+	if compiled, found := v.patterns.Load(pattern); found {
+		return compiled.(*regexp.Regexp)
+	}
+	compiled, e := regexp.Compile(pattern)
+	if e != nil {
+		compiled = nil
+	}
+	v.patterns.Store(pattern, compiled)
+	return compiled
+}
+
+// isEnumValue tests if the given value is one of the given enum values.
+func isEnumValue(value any, enum []any) bool {
+	text := fmt.Sprint(value)
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == text {
+			return true
+		}
+	}
+	return false
+}
+
+// escape escapes a property name to be used as part of a JSON pointer.
+func escape(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
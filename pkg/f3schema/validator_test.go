@@ -0,0 +1,107 @@
+package f3schema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3schema"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"testing"
+)
+
+const swaggerPath = "../../api/form3-swagger.yaml"
+
+func loadValidator(t *testing.T) *f3schema.Validator {
+	validator, e := f3schema.Load(swaggerPath)
+	if e != nil {
+		t.Fatalf("Failed to load the swagger: %s", e)
+	}
+	return validator
+}
+
+func newAccount() *f3.Account {
+	organisationId := "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	account := f3.NewAccount(&organisationId, "GB", "400300", "GBDSC", "Jane Smith", "41426819", "GBP", "")
+	account.Id = "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+	return account
+}
+
+func TestValidator_Validate(t *testing.T) {
+	validator := loadValidator(t)
+
+	valid, _ := json.Marshal(&f3.AccountEnvelope{Data: newAccount()})
+	if violations := validator.Validate("AccountCreation", valid); len(violations) > 0 {
+		t.Errorf("Expected the account to be valid, got: %v", violations)
+	}
+
+	invalid := `{"data":{"id":"42","type":"accounts","version":"1","attributes":{"country":"gb",
+		"name":["Jane Smith","","c","d","e"],"account_classification":"Private","bank_id":"400300"}}}`
+	expected := []f3.Violation{
+		{Pointer: "/data/attributes/account_classification", Message: "must be one of [Personal Business]"},
+		{Pointer: "/data/attributes/country", Message: "must match the pattern ^[A-Z]{2}$"},
+		{Pointer: "/data/attributes/name", Message: "must have at most 4 items"},
+		{Pointer: "/data/attributes/name/1", Message: "must have at least 1 characters"},
+		{Pointer: "/data/id", Message: "must be a valid uuid"},
+		{Pointer: "/data/organisation_id", Message: "is required"},
+		{Pointer: "/data/version", Message: "must be a integer"},
+	}
+	if violations := validator.Validate("AccountCreation", []byte(invalid)); fmt.Sprint(violations) != fmt.Sprint(expected) {
+		t.Errorf("Expected the violations\n%v\ngot\n%v", expected, violations)
+	}
+
+	if violations := validator.Validate("AccountCreation", []byte(`{"data":`)); len(violations) != 1 {
+		t.Errorf("Expected invalid JSON to be reported, got: %v", violations)
+	}
+	if violations := validator.Validate("Unknown", valid); len(violations) != 1 {
+		t.Errorf("Expected an unknown definition to be reported, got: %v", violations)
+	}
+}
+
+// TestValidator_Client validates the payloads of the client and the fake account API in strict mode.
+func TestValidator_Client(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	var logged []string
+	logger := f3.LoggerFunc(func(level f3.LogLevel, msg string, fields ...any) {
+		if msg == "Schema violation" {
+			logged = append(logged, fmt.Sprint(fields...))
+		}
+	})
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()), f3.WithLogger(logger),
+		f3.WithSchemaValidation(loadValidator(t), f3.ValidationStrict))
+
+	if !client.IsHealthy() {
+		t.Fatalf("Expected the fake account API to be healthy")
+	}
+	account := newAccount()
+	created, e := client.CreateAccount(account)
+	if e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+	changes := &f3.AccountChanges{Attr: &f3.AccountAttr{AccountClassification: "Business"}}
+	if _, e = client.PatchAccount(created.Id, *created.Version, changes); e != nil {
+		t.Fatalf("Failed to patch the account: %s", e.Error())
+	}
+	if _, e = client.ListAccounts(nil, nil); e != nil {
+		t.Fatalf("Failed to list the accounts: %s", e.Error())
+	}
+	if e = client.DeleteAccount(created.Id, 0); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Fatalf("Expected a conflict, got: %v", e)
+	}
+	if e = client.DeleteAccount(created.Id, 1); e != nil {
+		t.Fatalf("Failed to delete the account: %s", e.Error())
+	}
+	if len(logged) > 0 {
+		t.Errorf("Expected no violations, got: %q", logged)
+	}
+
+	invalid := newAccount()
+	invalid.Attr.Country = "Great Britain"
+	_, e = client.CreateAccount(invalid)
+	if e == nil || e.ErrorCode() != f3.ErrBadRequest {
+		t.Fatalf("Expected the invalid account to be rejected, got: %v", e)
+	}
+	if e.Unwrap().Error() != "Payload violates AccountCreation: /data/attributes/country: must match the pattern ^[A-Z]{2}$" {
+		t.Errorf("Unexpected violations: %s", e.Unwrap().Error())
+	}
+}
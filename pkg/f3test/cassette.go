@@ -0,0 +1,342 @@
+package f3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+// ScrubbedTime replaces all timestamps in recorded interactions.
+const ScrubbedTime = "2000-01-01T00:00:00.000Z"
+
+var (
+	uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	timePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+)
+
+// Cassette is a sequence of recorded interactions with the account API, stored as JSON fixture file. UUIDs are
+// replaced with placeholders in the order of their first appearance, like 00000000-0000-4000-8000-000000000001, and
+// timestamps with ScrubbedTime, so that the recording does not depend on generated ids or the time of the recording.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response of the account API.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a scrubbed request of an interaction.
+type RecordedRequest struct {
+	Method      string          `json:"method"`
+	Uri         string          `json:"uri"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Text        string          `json:"text,omitempty"`
+}
+
+// RecordedResponse is a scrubbed response of an interaction.
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+// LoadCassette reads the cassette from the given file.
+func LoadCassette(path string) (*Cassette, error) {
+	raw, e := ioutil.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+	var cassette Cassette
+	if e = json.Unmarshal(raw, &cassette); e != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, e)
+	}
+	for i := range cassette.Interactions {
+		interaction := &cassette.Interactions[i]
+		interaction.Request.Body = compact(interaction.Request.Body)
+		interaction.Response.Body = compact(interaction.Response.Body)
+	}
+	return &cassette, nil
+}
+
+// compact returns the given JSON without insignificant whitespace.
+func compact(body json.RawMessage) json.RawMessage {
+	var compacted bytes.Buffer
+	if len(body) == 0 || json.Compact(&compacted, body) != nil {
+		return body
+	}
+	return compacted.Bytes()
+}
+
+// Save writes the cassette to the given file, creating missing directories.
+func (c *Cassette) Save(path string) error {
+	raw, e := json.MarshalIndent(c, "", "  ")
+	if e != nil {
+		return e
+	}
+	if e = os.MkdirAll(filepath.Dir(path), 0755); e != nil {
+		return e
+	}
+	return ioutil.WriteFile(path, append(raw, '\n'), 0644)
+}
+
+// scrubber replaces UUIDs with placeholders and timestamps with ScrubbedTime.
+type scrubber struct {
+	placeholders map[string]string
+	values       map[string]string
+}
+
+func newScrubber() *scrubber {
+	return &scrubber{placeholders: map[string]string{}, values: map[string]string{}}
+}
+
+// bind binds the given value to the next placeholder, if it is not bound yet, and returns the placeholder.
+func (s *scrubber) bind(value string) string {
+	placeholder, found := s.placeholders[value]
+	if !found {
+		placeholder = fmt.Sprintf("00000000-0000-4000-8000-%012d", len(s.placeholders)+1)
+		s.placeholders[value] = placeholder
+		s.values[placeholder] = value
+	}
+	return placeholder
+}
+
+// scrub replaces all UUIDs and timestamps in the given text.
+func (s *scrubber) scrub(text string) string {
+	text = timePattern.ReplaceAllString(text, ScrubbedTime)
+	return uuidPattern.ReplaceAllStringFunc(text, s.bind)
+}
+
+// restore replaces the placeholders in the given recorded text with the bound values. Placeholders, that are not
+// bound yet, were generated by the account API during the recording, they are bound to themselves.
+func (s *scrubber) restore(text string) string {
+	return uuidPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, found := s.values[placeholder]; found {
+			return value
+		}
+		s.placeholders[placeholder] = placeholder
+		s.values[placeholder] = placeholder
+		return placeholder
+	})
+}
+
+// scrubBody returns the scrubbed body as JSON or, if the body is no JSON, as text.
+func (s *scrubber) scrubBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+	scrubbed := []byte(s.scrub(string(body)))
+	var compacted bytes.Buffer
+	if json.Compact(&compacted, scrubbed) != nil {
+		return nil, string(scrubbed)
+	}
+	return compacted.Bytes(), ""
+}
+
+// readRequestBody reads the body of the given request and replaces it, so that it can be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, e := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if e != nil {
+		return nil, e
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// Recorder is a http.RoundTripper recording all interactions with the account API into a cassette.
+//
+//	recorder := f3test.NewRecorder(nil)
+//	client := f3.NewClient(f3.WithEndPoint("http://localhost:8080/v1"), f3.WithTransport(recorder))
+//	...
+//	err := recorder.Cassette().Save("testdata/cassettes/accounts.json")
+type Recorder struct {
+	next     http.RoundTripper
+	mutex    sync.Mutex
+	scrubber *scrubber
+	cassette Cassette
+}
+
+// NewRecorder returns a recorder forwarding requests to the given transport, by default http.DefaultTransport.
+func NewRecorder(next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, scrubber: newScrubber()}
+}
+
+// Cassette returns a copy of the cassette recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, e := readRequestBody(req)
+	if e != nil {
+		return nil, e
+	}
+	resp, e := r.next.RoundTrip(req)
+	if e != nil {
+		return nil, e
+	}
+	respBody, e := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if e != nil {
+		return nil, e
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var interaction Interaction
+	interaction.Request.Method = req.Method
+	interaction.Request.Uri = r.scrubber.scrub(req.URL.RequestURI())
+	interaction.Request.ContentType = req.Header.Get("Content-Type")
+	interaction.Request.Body, interaction.Request.Text = r.scrubber.scrubBody(reqBody)
+	interaction.Response.Status = resp.StatusCode
+	interaction.Response.Header = http.Header{}
+	for name, values := range resp.Header {
+		if name == "Date" || name == "Content-Length" {
+			continue
+		}
+		for _, value := range values {
+			interaction.Response.Header.Add(name, r.scrubber.scrub(value))
+		}
+	}
+	interaction.Response.Body, interaction.Response.Text = r.scrubber.scrubBody(respBody)
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	return resp, nil
+}
+
+// Replayer is a http.RoundTripper serving the interactions of a cassette. Requests must match the recorded requests
+// in order, method, uri, content type and body are compared after scrubbing, JSON bodies semantically. Every mismatch
+// fails the test and the request, when the test ends, all interactions must have been replayed.
+//
+//	client := f3.NewClient(f3.WithEndPoint("http://replay/v1"), f3.WithTransport(f3test.NewReplayer(t, path)))
+type Replayer struct {
+	t        testing.TB
+	path     string
+	mutex    sync.Mutex
+	scrubber *scrubber
+	cassette *Cassette
+	next     int
+}
+
+// NewReplayer returns a replayer serving the cassette at the given path for the given test.
+func NewReplayer(t testing.TB, path string) *Replayer {
+	t.Helper()
+	cassette, e := LoadCassette(path)
+	if e != nil {
+		t.Fatalf("Failed to load the cassette: %s", e)
+	}
+	r := &Replayer{t: t, path: path, scrubber: newScrubber(), cassette: cassette}
+	t.Cleanup(func() {
+		if remaining := r.Remaining(); remaining > 0 {
+			t.Errorf("%d of %d interactions of cassette %s were not replayed", remaining, len(cassette.Interactions), path)
+		}
+	})
+	return r
+}
+
+// Remaining returns the amount of interactions not replayed yet.
+func (r *Replayer) Remaining() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.cassette.Interactions) - r.next
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, e := readRequestBody(req)
+	if e != nil {
+		return nil, e
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.next >= len(r.cassette.Interactions) {
+		e = fmt.Errorf("unexpected request %s %s, all %d interactions of cassette %s were replayed",
+			req.Method, req.URL.RequestURI(), len(r.cassette.Interactions), r.path)
+		r.t.Error(e)
+		return nil, e
+	}
+	interaction := &r.cassette.Interactions[r.next]
+	actual := RecordedRequest{
+		Method:      req.Method,
+		Uri:         r.scrubber.scrub(req.URL.RequestURI()),
+		ContentType: req.Header.Get("Content-Type"),
+	}
+	actual.Body, actual.Text = r.scrubber.scrubBody(body)
+	if mismatch := compareRequests(&interaction.Request, &actual); len(mismatch) > 0 {
+		e = fmt.Errorf("request %d of cassette %s does not match, %s", r.next+1, r.path, mismatch)
+		r.t.Error(e)
+		return nil, e
+	}
+	r.next++
+
+	recorded := &interaction.Response
+	resp := &http.Response{
+		Status:     fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode: recorded.Status,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	for name, values := range recorded.Header {
+		for _, value := range values {
+			resp.Header.Add(name, r.scrubber.restore(value))
+		}
+	}
+	respBody := recorded.Text
+	if len(recorded.Body) > 0 {
+		respBody = string(recorded.Body)
+	}
+	respBody = r.scrubber.restore(respBody)
+	resp.ContentLength = int64(len(respBody))
+	resp.Body = ioutil.NopCloser(bytes.NewBufferString(respBody))
+	return resp, nil
+}
+
+// compareRequests returns a description of the difference between the recorded and the actual request, if any.
+func compareRequests(recorded *RecordedRequest, actual *RecordedRequest) string {
+	if recorded.Method != actual.Method || recorded.Uri != actual.Uri {
+		return fmt.Sprintf("expected %s %s, got %s %s", recorded.Method, recorded.Uri, actual.Method, actual.Uri)
+	}
+	if recorded.ContentType != actual.ContentType {
+		return fmt.Sprintf("expected content type %q, got %q", recorded.ContentType, actual.ContentType)
+	}
+	if recorded.Text != actual.Text || !equalJson(recorded.Body, actual.Body) {
+		return fmt.Sprintf("expected body %s%s, got %s%s", recorded.Body, recorded.Text, actual.Body, actual.Text)
+	}
+	return ""
+}
+
+// equalJson tests if the given JSON documents are semantically equal.
+func equalJson(a json.RawMessage, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(left, right)
+}
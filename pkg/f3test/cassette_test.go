@@ -0,0 +1,107 @@
+package f3test_test
+
+import (
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failures is a test, that collects the errors instead of failing.
+type failures struct {
+	testing.TB
+	errors []string
+}
+
+func (f *failures) Error(args ...any) {
+	f.errors = append(f.errors, fmt.Sprint(args...))
+}
+
+func (f *failures) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// recordCassette records the creation and fetch of an account with a fake account API and returns the cassette path.
+func recordCassette(t *testing.T) (string, *f3.Account) {
+	server := f3test.NewServer()
+	defer server.Close()
+	recorder := f3test.NewRecorder(nil)
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()), f3.WithTransport(recorder))
+	account := newAccount(1, "GB")
+	if _, e := client.CreateAccount(account); e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+	if _, e := client.FetchAccount(account.Id); e != nil {
+		t.Fatalf("Failed to fetch the account: %s", e.Error())
+	}
+	path := filepath.Join(t.TempDir(), "cassettes", "create.json")
+	if e := recorder.Cassette().Save(path); e != nil {
+		t.Fatalf("Failed to save the cassette: %s", e)
+	}
+	return path, account
+}
+
+func TestCassette_Scrubbed(t *testing.T) {
+	path, account := recordCassette(t)
+	cassette, e := f3test.LoadCassette(path)
+	if e != nil {
+		t.Fatalf("Failed to load the cassette: %s", e)
+	}
+	if len(cassette.Interactions) != 2 {
+		t.Fatalf("Expected 2 interactions, got %d", len(cassette.Interactions))
+	}
+	created := string(cassette.Interactions[0].Response.Body)
+	if strings.Contains(created, account.Id) || strings.Contains(created, account.OrganisationId) {
+		t.Errorf("Expected the ids to be scrubbed, got: %s", created)
+	}
+	if !strings.Contains(created, f3test.ScrubbedTime) {
+		t.Errorf("Expected the timestamps to be scrubbed, got: %s", created)
+	}
+	if uri := cassette.Interactions[1].Request.Uri; uri != "/v1/organisation/accounts/00000000-0000-4000-8000-000000000001" {
+		t.Errorf("Expected the id in the uri to be scrubbed, got: %s", uri)
+	}
+}
+
+func TestCassette_Replay(t *testing.T) {
+	path, _ := recordCassette(t)
+	client := f3.NewClient(f3.WithEndPoint("http://replay/v1"), f3.WithTransport(f3test.NewReplayer(t, path)))
+
+	// Another id, the replayer binds it to the recorded placeholder.
+	account := newAccount(1, "GB")
+	account.Id = "9c4e5a7b-31f2-4c8e-8f55-0a1b2c3d4e5f"
+	created, e := client.CreateAccount(account)
+	if e != nil {
+		t.Fatalf("Failed to replay the creation: %s", e.Error())
+	}
+	if created.Id != account.Id || created.OrganisationId != account.OrganisationId {
+		t.Errorf("Expected the replayed ids to be restored, got %s and %s", created.Id, created.OrganisationId)
+	}
+	fetched, e := client.FetchAccount(account.Id)
+	if e != nil {
+		t.Fatalf("Failed to replay the fetch: %s", e.Error())
+	}
+	if fetched.Id != account.Id || fetched.CreatedOn == nil {
+		t.Errorf("Unexpected replayed account: %+v", fetched)
+	}
+}
+
+func TestCassette_ReplayMismatch(t *testing.T) {
+	path, _ := recordCassette(t)
+	test := &failures{TB: t}
+	replayer := f3test.NewReplayer(test, path)
+	client := f3.NewClient(f3.WithEndPoint("http://replay/v1"), f3.WithTransport(replayer),
+		f3.WithRetryPolicy(f3.NoRetry))
+
+	_, e := client.CreateAccount(newAccount(1, "DE"))
+	if e == nil || e.ErrorCode() != f3.ErrRequest {
+		t.Fatalf("Expected the changed request to fail, got: %v", e)
+	}
+	if len(test.errors) != 1 || !strings.Contains(test.errors[0], `"country":"DE"`) {
+		t.Errorf("Expected the test to fail with the mismatching body, got: %v", test.errors)
+	}
+	if replayer.Remaining() != 2 {
+		t.Errorf("Expected no interaction to be replayed, got %d remaining", replayer.Remaining())
+	}
+}
@@ -0,0 +1,204 @@
+package f3test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault describes how the FaultTransport treats a single request. The zero value forwards the request unchanged.
+type Fault struct {
+	// Match selects the requests the fault applies to; nil matches every request. Requests not matching the next
+	// fault of the plan are forwarded unchanged.
+	Match func(req *http.Request) bool
+
+	// Latency delays the request, before it is forwarded or answered; the delay ends early, when the context of the
+	// request is done.
+	Latency time.Duration
+
+	// Reset fails the request with a connection reset.
+	Reset bool
+
+	// Timeout fails the request with a network timeout.
+	Timeout bool
+
+	// Status answers the request with the given status code instead of forwarding it.
+	Status int
+
+	// RetryAfter is sent as Retry-After header of an answer with Status.
+	RetryAfter string
+
+	// Body is the body of an answer with Status.
+	Body string
+
+	// ContentType replaces the content type of the response.
+	ContentType string
+
+	// Truncate cuts the body of the response after the given amount of bytes, if greater than zero. The declared
+	// content length is kept, so reading the body fails with an unexpected EOF.
+	Truncate int
+
+	// Malformed replaces the body of the response with invalid JSON.
+	Malformed bool
+}
+
+// MalformedJson is the body of responses with a Malformed fault.
+const MalformedJson = `{"data":{"id":"`
+
+// FaultTransport is a http.RoundTripper injecting faults according to a plan into the requests of a client. Every
+// fault of the plan is applied to one request in order, when the plan is exhausted, requests are forwarded unchanged.
+//
+//	transport := f3test.NewFaultTransport(nil, f3test.Fault{Status: 503, RetryAfter: "1"}, f3test.Fault{Reset: true})
+//	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()), f3.WithTransport(transport))
+type FaultTransport struct {
+	next     http.RoundTripper
+	mutex    sync.Mutex
+	plan     []Fault
+	requests int
+	injected int
+}
+
+// NewFaultTransport returns a transport forwarding requests to the given transport, by default http.DefaultTransport,
+// after injecting the faults of the given plan.
+func NewFaultTransport(next http.RoundTripper, plan ...Fault) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &FaultTransport{next: next, plan: plan}
+}
+
+// Add appends the given faults to the plan.
+func (t *FaultTransport) Add(faults ...Fault) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.plan = append(t.plan, faults...)
+}
+
+// Requests returns the amount of requests sent through the transport.
+func (t *FaultTransport) Requests() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.requests
+}
+
+// Injected returns the amount of faults applied so far.
+func (t *FaultTransport) Injected() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.injected
+}
+
+// Pending returns the amount of faults of the plan, that were not applied yet.
+func (t *FaultTransport) Pending() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.plan)
+}
+
+// nextFault returns the fault for the given request, if any, and removes it from the plan.
+func (t *FaultTransport) nextFault(req *http.Request) (Fault, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.requests++
+	if len(t.plan) == 0 || (t.plan[0].Match != nil && !t.plan[0].Match(req)) {
+		return Fault{}, false
+	}
+	fault := t.plan[0]
+	t.plan = t.plan[1:]
+	t.injected++
+	return fault, true
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, found := t.nextFault(req)
+	if !found {
+		return t.next.RoundTrip(req)
+	}
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if fault.Reset {
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+	if fault.Timeout {
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+	}
+	var resp *http.Response
+	if fault.Status > 0 {
+		closeBody(req)
+		resp = &http.Response{
+			Status:     strconv.Itoa(fault.Status) + " " + http.StatusText(fault.Status),
+			StatusCode: fault.Status,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": {"application/vnd.api+json"}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString(fault.Body)),
+			Request:    req,
+		}
+		if len(fault.RetryAfter) > 0 {
+			resp.Header.Set("Retry-After", fault.RetryAfter)
+		}
+	} else {
+		var e error
+		if resp, e = t.next.RoundTrip(req); e != nil {
+			return nil, e
+		}
+	}
+	if len(fault.ContentType) > 0 {
+		resp.Header.Set("Content-Type", fault.ContentType)
+	}
+	if fault.Malformed {
+		_ = resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewBufferString(MalformedJson))
+		resp.ContentLength = int64(len(MalformedJson))
+	}
+	if fault.Truncate > 0 {
+		resp.Body = &truncatedBody{io.LimitReader(resp.Body, int64(fault.Truncate)), resp.Body}
+	}
+	return resp, nil
+}
+
+// closeBody closes the body of a request, that is not forwarded, as required from a http.RoundTripper.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
+
+// timeoutError is the network error of a Timeout fault.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// truncatedBody is a response body ending early with an unexpected EOF.
+type truncatedBody struct {
+	reader io.Reader
+	io.Closer
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, e := b.reader.Read(p)
+	if e == io.EOF {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, e
+}
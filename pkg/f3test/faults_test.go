@@ -0,0 +1,107 @@
+package f3test_test
+
+import (
+	"context"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newFaultClient returns a client for a fake account API holding one account, whose requests are sent through a
+// transport injecting the given faults.
+func newFaultClient(t *testing.T, policy f3.RetryPolicy, faults ...f3test.Fault) (*f3.Client, *f3test.FaultTransport, string) {
+	server := f3test.NewServer()
+	t.Cleanup(server.Close)
+	account := newAccount(1, "GB")
+	server.Put(account)
+	transport := f3test.NewFaultTransport(nil, faults...)
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()), f3.WithTransport(transport), f3.WithRetryPolicy(policy))
+	return client, transport, account.Id
+}
+
+func TestFaultTransport_ErrorPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault f3test.Fault
+		code  int
+	}{
+		{"connection reset", f3test.Fault{Reset: true}, f3.ErrRequest},
+		{"network timeout", f3test.Fault{Timeout: true}, f3.ErrTimeout},
+		{"malformed json", f3test.Fault{Malformed: true}, f3.ErrResponse},
+		{"truncated body", f3test.Fault{Truncate: 10}, f3.ErrResponse},
+		{"wrong content type", f3test.Fault{Status: 200, ContentType: "text/html", Body: "<html></html>"}, f3.ErrResponse},
+		{"empty body", f3test.Fault{Status: 200}, f3.ErrResponse},
+		{"bad request", f3test.Fault{Status: 400, Body: `{"error_message":"invalid account"}`}, f3.ErrBadRequest},
+		{"rate limited", f3test.Fault{Status: 429, RetryAfter: "1"}, f3.ErrRateLimited},
+		{"server error", f3test.Fault{Status: 503, RetryAfter: "1"}, f3.ErrServer},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, transport, id := newFaultClient(t, f3.NoRetry, test.fault)
+			_, e := client.FetchAccount(id)
+			if e == nil || e.ErrorCode() != test.code {
+				t.Fatalf("Expected the error code %d, got: %v", test.code, e)
+			}
+			if transport.Injected() != 1 {
+				t.Errorf("Expected the fault to be injected once, but was injected %d times", transport.Injected())
+			}
+			if _, e = client.FetchAccount(id); e != nil {
+				t.Errorf("Expected the request after the fault to succeed, got: %s", e.Error())
+			}
+		})
+	}
+}
+
+func TestFaultTransport_Retry(t *testing.T) {
+	policy := f3.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxRetryAfter: time.Second}
+	client, transport, id := newFaultClient(t, policy,
+		f3test.Fault{Status: 429, RetryAfter: "0"},
+		f3test.Fault{Status: 503, RetryAfter: "0"},
+		f3test.Fault{Reset: true},
+	)
+
+	account, e := client.FetchAccount(id)
+	if e != nil {
+		t.Fatalf("Expected the request to be retried until it succeeds, got: %s", e.Error())
+	}
+	if account.Id != id {
+		t.Errorf("Expected the account %s, got %s", id, account.Id)
+	}
+	if transport.Requests() != 4 || transport.Pending() != 0 {
+		t.Errorf("Expected 4 requests and all faults applied, got %d requests and %d pending faults",
+			transport.Requests(), transport.Pending())
+	}
+}
+
+func TestFaultTransport_Latency(t *testing.T) {
+	client, _, id := newFaultClient(t, f3.NoRetry, f3test.Fault{Latency: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, e := client.FetchAccountWithContext(ctx, id)
+	if e == nil || e.ErrorCode() != f3.ErrTimeout {
+		t.Fatalf("Expected a timeout, got: %v", e)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("Expected the latency to end with the deadline of the context")
+	}
+}
+
+func TestFaultTransport_Match(t *testing.T) {
+	onlyPost := func(req *http.Request) bool { return req.Method == http.MethodPost }
+	client, transport, id := newFaultClient(t, f3.NoRetry, f3test.Fault{Match: onlyPost, Status: 500})
+
+	if _, e := client.FetchAccount(id); e != nil {
+		t.Fatalf("Expected the fetch not to match the fault, got: %s", e.Error())
+	}
+	_, e := client.CreateAccount(newAccount(2, "GB"))
+	if e == nil || e.ErrorCode() != f3.ErrServer {
+		t.Fatalf("Expected the create to fail with a server error, got: %v", e)
+	}
+	if transport.Pending() != 0 {
+		t.Errorf("Expected the fault to be applied")
+	}
+}
@@ -0,0 +1,405 @@
+// Package f3test provides an in-memory fake of the Form3 account API for tests, that do not have access to the real
+// or the dockerized account API.
+//
+//	server := f3test.NewServer()
+//	defer server.Close()
+//	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()))
+package f3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPageSize is the page size used, when a list request does not select one.
+const DefaultPageSize = 100
+
+const accountsPath = "/v1/organisation/accounts"
+
+// Server is a fake account API serving /v1/health and /v1/organisation/accounts from memory. It behaves like the
+// account API of the Form3 docker image: accounts are created with version 0, duplicates are rejected with 409,
+// deletes and patches require the current version.
+type Server struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	accounts map[string]*f3.Account
+	position map[string]int
+	sequence int
+	healthy  bool
+	now      func() time.Time
+}
+
+// NewServer starts a new fake account API without any accounts. The server must be closed after use.
+func NewServer() *Server {
+	s := &Server{accounts: map[string]*f3.Account{}, position: map[string]int{}, healthy: true, now: time.Now}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/health", s.health)
+	mux.HandleFunc(accountsPath, s.accountList)
+	mux.HandleFunc(accountsPath+"/", s.account)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// EndPoint returns the endpoint to configure the client with, see f3.WithEndPoint.
+func (s *Server) EndPoint() string {
+	return s.URL + "/v1"
+}
+
+// SetHealthy changes the status reported by the health check.
+func (s *Server) SetHealthy(healthy bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.healthy = healthy
+}
+
+// Put stores a copy of the given account as it is, replacing any account with the same id. If the account has no
+// version, version 0 is used.
+func (s *Server) Put(account *f3.Account) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stored := clone(account)
+	if stored.Version == nil {
+		version := uint64(0)
+		stored.Version = &version
+	}
+	s.store(stored)
+}
+
+// store stores the given account and keeps its position, if it replaces an account. The caller must hold the mutex.
+func (s *Server) store(account *f3.Account) {
+	if _, exists := s.position[account.Id]; !exists {
+		s.sequence++
+		s.position[account.Id] = s.sequence
+	}
+	s.accounts[account.Id] = account
+}
+
+// Account returns a copy of the stored account with the given id or nil, if it does not exist.
+func (s *Server) Account(accountId string) *f3.Account {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if account, found := s.accounts[accountId]; found {
+		return clone(account)
+	}
+	return nil
+}
+
+// Accounts returns copies of all stored accounts ordered by creation.
+func (s *Server) Accounts() []*f3.Account {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	accounts := s.sorted()
+	for i, account := range accounts {
+		accounts[i] = clone(account)
+	}
+	return accounts
+}
+
+// sorted returns the stored accounts ordered by creation, the caller must hold the mutex.
+func (s *Server) sorted() []*f3.Account {
+	accounts := make([]*f3.Account, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return s.position[accounts[i].Id] < s.position[accounts[j].Id]
+	})
+	return accounts
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	status := "up"
+	if !s.healthy {
+		status = "down"
+	}
+	s.mutex.Unlock()
+	writeJson(w, http.StatusOK, &f3.HealthyResponse{Status: &status})
+}
+
+func (s *Server) accountList(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.list(w, r)
+	case http.MethodPost:
+		s.create(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) account(w http.ResponseWriter, r *http.Request) {
+	accountId := strings.TrimPrefix(r.URL.Path, accountsPath+"/")
+	if len(accountId) == 0 || strings.Contains(accountId, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.fetch(w, accountId)
+	case http.MethodDelete:
+		s.delete(w, r, accountId)
+	case http.MethodPatch:
+		s.patch(w, r, accountId)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var envelope f3.AccountEnvelope
+	if e := json.NewDecoder(r.Body).Decode(&envelope); e != nil || envelope.Data == nil {
+		writeError(w, http.StatusBadRequest, "validation failure list:\ndata in body is required")
+		return
+	}
+	account := envelope.Data
+	if failures := validate(account); len(failures) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(failures, "\n"))
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, exists := s.accounts[account.Id]; exists {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+	now := s.now().UTC()
+	version := uint64(0)
+	account.CreatedOn, account.ModifiedOn, account.Version = &now, &now, &version
+	s.store(clone(account))
+	writeJson(w, http.StatusCreated, &f3.AccountEnvelope{Data: account})
+}
+
+// validate returns the validation failures of an account to create.
+func validate(account *f3.Account) []string {
+	var failures []string
+	if len(account.Id) == 0 {
+		failures = append(failures, "id in body is required")
+	}
+	if len(account.OrganisationId) == 0 {
+		failures = append(failures, "organisation_id in body is required")
+	}
+	if account.Type != f3.TypeAccount {
+		failures = append(failures, fmt.Sprintf("type in body should be one of [%s]", f3.TypeAccount))
+	}
+	if account.Attr == nil {
+		failures = append(failures, "attributes in body is required")
+	} else if len(account.Attr.Country) == 0 {
+		failures = append(failures, "country in body is required")
+	}
+	return failures
+}
+
+func (s *Server) fetch(w http.ResponseWriter, accountId string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	account, found := s.accounts[accountId]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", accountId))
+		return
+	}
+	writeJson(w, http.StatusOK, &f3.AccountEnvelope{Data: account})
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, accountId string) {
+	version, e := strconv.ParseUint(r.URL.Query().Get("version"), 10, 64)
+	if e != nil {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	account, found := s.accounts[accountId]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if *account.Version != version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	delete(s.accounts, accountId)
+	delete(s.position, accountId)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, accountId string) {
+	var patch struct {
+		Data *struct {
+			Version    *uint64                    `json:"version"`
+			Attributes map[string]json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if e := json.NewDecoder(r.Body).Decode(&patch); e != nil || patch.Data == nil || patch.Data.Version == nil {
+		writeError(w, http.StatusBadRequest, "validation failure list:\nversion in body is required")
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	account, found := s.accounts[accountId]
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", accountId))
+		return
+	}
+	if *account.Version != *patch.Data.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+	var attributes map[string]json.RawMessage
+	raw, _ := json.Marshal(account.Attr)
+	_ = json.Unmarshal(raw, &attributes)
+	if attributes == nil {
+		attributes = map[string]json.RawMessage{}
+	}
+	for name, value := range patch.Data.Attributes {
+		if string(value) == "null" {
+			delete(attributes, name)
+		} else {
+			attributes[name] = value
+		}
+	}
+	raw, _ = json.Marshal(attributes)
+	patched := clone(account)
+	patched.Attr = new(f3.AccountAttr)
+	if e := json.Unmarshal(raw, patched.Attr); e != nil {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+e.Error())
+		return
+	}
+	now := s.now().UTC()
+	version := *account.Version + 1
+	patched.ModifiedOn, patched.Version = &now, &version
+	s.accounts[accountId] = patched
+	writeJson(w, http.StatusOK, &f3.AccountEnvelope{Data: patched})
+}
+
+// filters are the list filters supported by the account API by name.
+var filters = map[string]func(*f3.AccountAttr) string{
+	"bank_id_code":   func(a *f3.AccountAttr) string { return a.BankIdCode },
+	"bank_id":        func(a *f3.AccountAttr) string { return a.BankId },
+	"account_number": func(a *f3.AccountAttr) string { return a.AccountNumber },
+	"country":        func(a *f3.AccountAttr) string { return a.Country },
+	"iban":           func(a *f3.AccountAttr) string { return a.Iban },
+	"customer_id": func(a *f3.AccountAttr) string {
+		if a.CustomerId == nil {
+			return ""
+		}
+		return *a.CustomerId
+	},
+}
+
+// matches returns true, if the account matches all filters of the given query.
+func matches(account *f3.Account, query url.Values) bool {
+	for name, values := range query {
+		if !strings.HasPrefix(name, "filter[") || !strings.HasSuffix(name, "]") {
+			continue
+		}
+		field := strings.TrimSuffix(strings.TrimPrefix(name, "filter["), "]")
+		var value string
+		if field == "organisation_id" {
+			value = account.OrganisationId
+		} else if get, found := filters[field]; found && account.Attr != nil {
+			value = get(account.Attr)
+		}
+		accepted := false
+		for _, v := range strings.Split(strings.Join(values, ","), ",") {
+			accepted = accepted || v == value
+		}
+		if !accepted {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	size := DefaultPageSize
+	if raw := query.Get("page[size]"); len(raw) > 0 {
+		parsed, e := strconv.Atoi(raw)
+		if e != nil || parsed < 0 || parsed > f3.MaxPageSize {
+			writeError(w, http.StatusBadRequest, "invalid page size")
+			return
+		}
+		if parsed > 0 {
+			size = parsed
+		}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var matching []*f3.Account
+	for _, account := range s.sorted() {
+		if matches(account, query) {
+			matching = append(matching, account)
+		}
+	}
+	last := 0
+	if len(matching) > 0 {
+		last = (len(matching) - 1) / size
+	}
+	number := 0
+	switch raw := query.Get("page[number]"); raw {
+	case "", "first":
+	case "last":
+		number = last
+	default:
+		parsed, e := strconv.Atoi(raw)
+		if e != nil || parsed < 0 {
+			writeError(w, http.StatusBadRequest, "invalid page number")
+			return
+		}
+		number = parsed
+	}
+	envelope := f3.AccountsEnvelope{Data: []*f3.Account{}, Links: &f3.Links{
+		Self:  r.URL.RequestURI(),
+		First: pageUri(query, 0, size),
+		Last:  pageUri(query, last, size),
+	}}
+	for i := number * size; i < len(matching) && i < (number+1)*size; i++ {
+		envelope.Data = append(envelope.Data, matching[i])
+	}
+	if number < last {
+		envelope.Links.Next = pageUri(query, number+1, size)
+	}
+	if number > 0 && number <= last {
+		envelope.Links.Prev = pageUri(query, number-1, size)
+	}
+	writeJson(w, http.StatusOK, &envelope)
+}
+
+// pageUri returns the uri of the given page with the filters of the given query.
+func pageUri(query url.Values, number int, size int) string {
+	page := url.Values{}
+	for name, values := range query {
+		page[name] = values
+	}
+	page.Set("page[number]", strconv.Itoa(number))
+	page.Set("page[size]", strconv.Itoa(size))
+	return accountsPath + "?" + page.Encode()
+}
+
+// clone returns a deep copy of the given account.
+func clone(account *f3.Account) *f3.Account {
+	raw, _ := json.Marshal(account)
+	var copied f3.Account
+	_ = json.Unmarshal(raw, &copied)
+	return &copied
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJson(w, status, &f3.ErrorResponse{ErrorMessage: message})
+}
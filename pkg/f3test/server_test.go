@@ -0,0 +1,131 @@
+package f3test_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3"
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"testing"
+)
+
+func newAccount(id int, country string) *f3.Account {
+	organisationId := "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+	account := f3.NewAccount(&organisationId, country, "400300", "GBDSC", "Jane Smith", fmt.Sprintf("%08d", id), "GBP", "")
+	account.Id = fmt.Sprintf("ad27e265-9605-4b4b-a0e5-%012d", id)
+	return account
+}
+
+func TestServer_Health(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()))
+
+	if !client.IsHealthy() {
+		t.Errorf("Expected the server to be healthy")
+	}
+	server.SetHealthy(false)
+	if client.IsHealthy() {
+		t.Errorf("Expected the server to be unhealthy")
+	}
+}
+
+func TestServer_CreateFetchDelete(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()))
+
+	created, e := client.CreateAccount(newAccount(1, "GB"))
+	if e != nil {
+		t.Fatalf("Failed to create the account: %s", e.Error())
+	}
+	if created.Version == nil || *created.Version != 0 || created.CreatedOn == nil {
+		t.Errorf("Expected the server to set version and creation time, but got: %+v", created.Resource)
+	}
+	if _, e = client.CreateAccount(newAccount(1, "GB")); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for a duplicate id, but got: %v", e)
+	}
+	invalid := newAccount(2, "")
+	if _, e = client.CreateAccount(invalid); e == nil || e.ErrorCode() != f3.ErrBadRequest {
+		t.Errorf("Expected a bad request for an account without country, but got: %v", e)
+	}
+
+	fetched, e := client.FetchAccount(created.Id)
+	if e != nil || fetched.Attr.AccountNumber != "00000001" {
+		t.Fatalf("Failed to fetch the account: %v", e)
+	}
+	if _, e = client.FetchAccount(invalid.Id); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected not found for an unknown account, but got: %v", e)
+	}
+
+	if e = client.DeleteAccount(created.Id, 1); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for the wrong version, but got: %v", e)
+	}
+	if e = client.DeleteAccount(created.Id, 0); e != nil {
+		t.Fatalf("Failed to delete the account: %s", e.Error())
+	}
+	if e = client.DeleteAccount(created.Id, 0); e == nil || e.ErrorCode() != f3.ErrNotFound {
+		t.Errorf("Expected not found for a deleted account, but got: %v", e)
+	}
+}
+
+func TestServer_Patch(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()))
+	account := newAccount(1, "GB")
+	server.Put(account)
+
+	updated, e := client.UpdateAccount(account.Id, func(account *f3.Account) error {
+		account.Attr.WithStatusClosed("deceased")
+		return nil
+	})
+	if e != nil {
+		t.Fatalf("Failed to update the account: %s", e.Error())
+	}
+	if *updated.Version != 1 || updated.Attr.Status != f3.StatusClosed || updated.Attr.Country != "GB" {
+		t.Errorf("Patched the account wrong: %+v", updated.Attr)
+	}
+	changes := &f3.AccountChanges{Attr: &f3.AccountAttr{BankId: "400301"}}
+	if _, e = client.PatchAccount(account.Id, 0, changes); e == nil || e.ErrorCode() != f3.ErrConflict {
+		t.Errorf("Expected a conflict for the outdated version, but got: %v", e)
+	}
+	if stored := server.Account(account.Id); *stored.Version != 1 || stored.Attr.BankId != "400300" {
+		t.Errorf("Expected the rejected patch not to change the account, but got: %+v", stored.Attr)
+	}
+}
+
+func TestServer_List(t *testing.T) {
+	server := f3test.NewServer()
+	defer server.Close()
+	client := f3.NewClient(f3.WithEndPoint(server.EndPoint()))
+	for i := 0; i < 25; i++ {
+		country := "GB"
+		if i%5 == 0 {
+			country = "DE"
+		}
+		server.Put(newAccount(i, country))
+	}
+
+	envelope, e := client.ListAccounts(&f3.AccountFilter{Country: []string{"GB"}}, &f3.Page{Number: 1, Size: 8})
+	if e != nil {
+		t.Fatalf("Failed to list the accounts: %s", e.Error())
+	}
+	if len(envelope.Data) != 8 || envelope.Data[0].Attr.AccountNumber != "00000011" {
+		t.Errorf("Listed the wrong page: %d accounts, first %s", len(envelope.Data), envelope.Data[0].Attr.AccountNumber)
+	}
+	if envelope.Links.Next == "" || envelope.Links.Prev == "" {
+		t.Errorf("Expected links to the next and previous page, but got: %+v", envelope.Links)
+	}
+
+	count := 0
+	it := client.IterateAccounts(context.Background(), &f3.AccountFilter{Country: []string{"DE"}}, 2)
+	for it.Next() {
+		if it.Account().Attr.Country != "DE" {
+			t.Errorf("Listed an account that does not match the filter: %s", it.Account().Id)
+		}
+		count++
+	}
+	if it.Err() != nil || count != 5 {
+		t.Errorf("Expected 5 accounts in DE, but got %d: %v", count, it.Err())
+	}
+}
@@ -0,0 +1,345 @@
+package f3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/xeus2001/interview-accountapi/pkg/f3schema"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Swagger is a swagger specification, see f3schema.Swagger.
+type Swagger = f3schema.Swagger
+
+// Schema is a swagger schema of a definition or property, see f3schema.Schema.
+type Schema = f3schema.Schema
+
+// LoadSwagger reads the swagger specification from the given YAML file.
+func LoadSwagger(path string) (*Swagger, error) {
+	return f3schema.LoadSwagger(path)
+}
+
+// Conformance compares the JSON mapping of Go types with definitions of a swagger specification.
+type Conformance struct {
+	// Swagger is the specification to compare with.
+	Swagger *Swagger
+
+	// Enums are the values of the enum types by type name, like "f3.AccountStatusString", see ParseEnums. Properties
+	// with enum of the swagger must be of such a type or of type string, in which case the values are not compared.
+	Enums map[string][]string
+
+	// Ignore are the paths of drifts to ignore, like "Account.created_on", for known deviations of the account API
+	// from the swagger. A * matches any sequence of characters, like "*.created_on".
+	Ignore []string
+}
+
+// Check compares the JSON mapping of the type of the given value with the swagger definition of the given name and
+// returns the drifts found, prefixed with the path of the property. Structures are compared recursively, properties
+// must have a field with the same JSON name and a compatible type, enum types must have the same values and fields
+// must be defined by the swagger. The type is compared as read shape, the body of a response, which is decoded.
+func (c *Conformance) Check(definition string, value any) []string {
+	return c.check(definition, value, false)
+}
+
+// CheckRequest compares the type of the given value with the swagger definition of the given name like Check, but as
+// write shape, the body of a request, which is encoded. Read only properties, which are set by the account API, need
+// not be mapped, but must be omitted when empty. Optional properties must be omitted when empty, so that no empty
+// values are sent, and required properties must not be omitted, when their empty value is valid.
+func (c *Conformance) CheckRequest(definition string, value any) []string {
+	return c.check(definition, value, true)
+}
+
+func (c *Conformance) check(definition string, value any, write bool) []string {
+	schema, found := c.Swagger.Definitions[definition]
+	if !found {
+		return []string{fmt.Sprintf("%s: definition not found in the swagger", definition)}
+	}
+	check := &conformanceCheck{Conformance: c, write: write, visited: map[visit]bool{}}
+	check.compare(definition, reflect.TypeOf(value), schema)
+	sort.Strings(check.drifts)
+	return check.drifts
+}
+
+// visit is a type compared with a schema, to stop the recursion for cyclic references.
+type visit struct {
+	t      reflect.Type
+	schema *Schema
+}
+
+type conformanceCheck struct {
+	*Conformance
+	write   bool
+	visited map[visit]bool
+	drifts  []string
+}
+
+func (c *conformanceCheck) drift(path string, format string, args ...any) {
+	for _, ignored := range c.Ignore {
+		if matchGlob(ignored, path) {
+			return
+		}
+	}
+	c.drifts = append(c.drifts, path+": "+fmt.Sprintf(format, args...))
+}
+
+// matchGlob tests if the given text matches the given pattern, in which * matches any sequence of characters.
+func matchGlob(pattern string, text string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(text, parts[0]) {
+		return false
+	}
+	text = text[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 {
+			return strings.HasSuffix(text, part)
+		}
+		index := strings.Index(text, part)
+		if index < 0 {
+			return false
+		}
+		text = text[index+len(part):]
+	}
+	return len(text) == 0
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// compare compares the given type with the given schema.
+func (c *conformanceCheck) compare(path string, t reflect.Type, schema *Schema) {
+	schema = c.Swagger.Resolve(schema)
+	if schema == nil {
+		c.drift(path, "unresolvable reference")
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface || t == rawType {
+		return
+	}
+	if c.visited[visit{t, schema}] {
+		return
+	}
+	c.visited[visit{t, schema}] = true
+
+	schemaType := schema.Type
+	if len(schemaType) == 0 && len(schema.Properties) > 0 {
+		schemaType = "object"
+	}
+	var compatible bool
+	switch schemaType {
+	case "string":
+		compatible = t.Kind() == reflect.String || (t == timeType && schema.Format == "date-time")
+	case "integer":
+		compatible = t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64
+	case "number":
+		compatible = (t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64) || t.Kind() == reflect.Float32 ||
+			t.Kind() == reflect.Float64
+	case "boolean":
+		compatible = t.Kind() == reflect.Bool
+	case "array":
+		compatible = t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+		if compatible && schema.Items != nil {
+			c.compare(path+"[]", t.Elem(), schema.Items)
+		}
+	case "object", "":
+		compatible = t.Kind() == reflect.Map || t.Kind() == reflect.Struct
+		if t.Kind() == reflect.Struct && t != timeType {
+			c.compareStruct(path, t, schema)
+		}
+	}
+	if !compatible {
+		c.drift(path, "type %s is incompatible with %s", t, strings.TrimSpace(schemaType+" "+schema.Format))
+		return
+	}
+	if len(schema.Enum) > 0 && t.Kind() == reflect.String && len(t.PkgPath()) > 0 {
+		c.compareEnum(path, t, schema)
+	}
+}
+
+// compareStruct compares the fields of the given struct with the properties of the given schema.
+func (c *conformanceCheck) compareStruct(path string, t reflect.Type, schema *Schema) {
+	fields := map[string]jsonField{}
+	jsonFields(t, fields)
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for name, property := range schema.Properties {
+		field, found := fields[name]
+		readOnly := c.write && c.readOnly(property)
+		switch {
+		case !found && readOnly:
+		case !found && required[name]:
+			c.drift(path+"."+name, "required property is not mapped")
+		case !found:
+			c.drift(path+"."+name, "property is not mapped")
+		default:
+			if c.write {
+				kept := (required[name] && !readOnly) || (property.OmitEmpty != nil && !*property.OmitEmpty)
+				c.compareOmission(path+"."+name, field, property, kept)
+			}
+			c.compare(path+"."+name, field.t, property)
+		}
+	}
+	for name, field := range fields {
+		if _, found := schema.Properties[name]; found {
+			continue
+		}
+		// The resources are shared by requests and responses, fields set by the account API only are not sent empty.
+		if !c.write {
+			c.drift(path+"."+name, "field is not defined by the swagger")
+		} else if !field.omitempty {
+			c.drift(path+"."+name, "field is not defined by the swagger and sent, when empty")
+		}
+	}
+}
+
+// readOnly tests if the given property is set by the account API only, either itself or the referenced definition.
+func (c *conformanceCheck) readOnly(property *Schema) bool {
+	resolved := c.Swagger.Resolve(property)
+	return property.ReadOnly || (resolved != nil && resolved.ReadOnly)
+}
+
+// compareOmission compares the omission of the given field, when empty, with the given property of a write shape.
+// Optional properties must be omitted when empty and kept properties, which are required or have x-omitempty false,
+// must not be omitted, when the empty value is valid.
+func (c *conformanceCheck) compareOmission(path string, field jsonField, property *Schema, kept bool) {
+	switch {
+	case !kept && !field.omitempty:
+		c.drift(path, "optional property is mapped to a field without omitempty")
+	case kept && field.omitempty && c.validEmpty(field.t, property):
+		c.drift(path, "required property is mapped to a field with omitempty, which drops the valid empty value")
+	}
+}
+
+// validEmpty tests if the empty value of the given type is a valid value of the given schema.
+func (c *conformanceCheck) validEmpty(t reflect.Type, schema *Schema) bool {
+	schema = c.Swagger.Resolve(schema)
+	if schema == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return len(schema.Enum) == 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return len(schema.Enum) == 0 && (schema.Minimum == nil || *schema.Minimum <= 0) &&
+			(schema.Maximum == nil || *schema.Maximum >= 0)
+	case reflect.String:
+		return len(schema.Enum) == 0 && len(schema.Format) == 0 && len(schema.Pattern) == 0 &&
+			(schema.MinLength == nil || *schema.MinLength == 0)
+	}
+	return false
+}
+
+// jsonField is the type of a field and if it is omitted, when empty.
+type jsonField struct {
+	t         reflect.Type
+	omitempty bool
+}
+
+// jsonFields collects the exported fields of the given struct and its embedded structs by JSON name.
+func jsonFields(t reflect.Type, fields map[string]jsonField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		options := strings.Split(field.Tag.Get("json"), ",")
+		name := options[0]
+		if field.Anonymous && len(name) == 0 && field.Type.Kind() == reflect.Struct {
+			jsonFields(field.Type, fields)
+			continue
+		}
+		if len(field.PkgPath) > 0 || name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		omitempty := false
+		for _, option := range options[1:] {
+			omitempty = omitempty || option == "omitempty"
+		}
+		fields[name] = jsonField{t: field.Type, omitempty: omitempty}
+	}
+}
+
+// compareEnum compares the values of the given enum type with the enum of the given schema.
+func (c *conformanceCheck) compareEnum(path string, t reflect.Type, schema *Schema) {
+	values, found := c.Enums[t.String()]
+	if !found {
+		c.drift(path, "values of enum type %s are unknown", t)
+		return
+	}
+	expected := map[string]bool{}
+	for _, value := range schema.Enum {
+		expected[fmt.Sprint(value)] = true
+	}
+	for _, value := range values {
+		if !expected[value] {
+			c.drift(path, "enum value %q of %s is not defined by the swagger", value, t)
+		}
+		delete(expected, value)
+	}
+	for value := range expected {
+		c.drift(path, "enum value %q is missing in %s", value, t)
+	}
+}
+
+// ParseEnums parses the Go sources of the package in the given directory and returns the values of all typed string
+// constants by the qualified name of their type, like "f3.AccountStatusString".
+func ParseEnums(dir string) (map[string][]string, error) {
+	packages, e := parser.ParseDir(token.NewFileSet(), dir, nil, 0)
+	if e != nil {
+		return nil, e
+	}
+	enums := map[string][]string{}
+	for name, pkg := range packages {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(node ast.Node) bool {
+				spec, ok := node.(*ast.ValueSpec)
+				if !ok {
+					return true
+				}
+				for i, value := range spec.Values {
+					typeName, literal := enumConstant(spec, value)
+					if len(typeName) > 0 && i < len(spec.Names) {
+						enums[name+"."+typeName] = append(enums[name+"."+typeName], literal)
+					}
+				}
+				return false
+			})
+		}
+	}
+	return enums, nil
+}
+
+// enumConstant returns the type name and value of a typed string constant, declared either with "T = T("value")"
+// or "T T = "value"", otherwise empty strings.
+func enumConstant(spec *ast.ValueSpec, value ast.Expr) (string, string) {
+	var typeName ast.Expr = spec.Type
+	if call, ok := value.(*ast.CallExpr); ok && typeName == nil && len(call.Args) == 1 {
+		typeName, value = call.Fun, call.Args[0]
+	}
+	ident, ok := typeName.(*ast.Ident)
+	literal, isLiteral := value.(*ast.BasicLit)
+	if !ok || !isLiteral || literal.Kind != token.STRING {
+		return "", ""
+	}
+	unquoted, e := strconv.Unquote(literal.Value)
+	if e != nil {
+		return "", ""
+	}
+	return ident.Name, unquoted
+}
@@ -0,0 +1,147 @@
+package f3test_test
+
+import (
+	"github.com/xeus2001/interview-accountapi/pkg/f3test"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testSwagger = `
+swagger: '2.0'
+definitions:
+  Thing:
+    properties:
+      id:
+        format: uuid
+        type: string
+      created_on:
+        format: date-time
+        readOnly: true
+        type: string
+      count:
+        type: integer
+      state:
+        $ref: '#/definitions/State'
+      tags:
+        items:
+          type: string
+        type: array
+      name:
+        type: string
+      parent:
+        $ref: '#/definitions/Thing'
+      done:
+        type: boolean
+        x-omitempty: false
+    required:
+      - id
+      - name
+    type: object
+  State:
+    enum:
+      - new
+      - done
+    type: string
+`
+
+type state string
+
+const (
+	stateNew  = state("new")
+	stateGone = state("gone")
+)
+
+type thing struct {
+	Id        string     `json:"id,omitempty"`
+	CreatedOn *time.Time `json:"created_on,omitempty"`
+	Count     string     `json:"count"`
+	State     state      `json:"state,omitempty"`
+	Tags      []string   `json:"tags"`
+	Parent    *thing     `json:"parent,omitempty"`
+	Done      bool       `json:"done,omitempty"`
+	Extra     bool       `json:"extra"`
+	Ignored   bool       `json:"-"`
+	internal  bool
+}
+
+func loadTestSwagger(t *testing.T) *f3test.Swagger {
+	path := filepath.Join(t.TempDir(), "swagger.yaml")
+	if e := os.WriteFile(path, []byte(testSwagger), 0644); e != nil {
+		t.Fatal(e)
+	}
+	swagger, e := f3test.LoadSwagger(path)
+	if e != nil {
+		t.Fatalf("Failed to load the swagger: %s", e)
+	}
+	return swagger
+}
+
+func TestConformance_Check(t *testing.T) {
+	conformance := &f3test.Conformance{
+		Swagger: loadTestSwagger(t),
+		Enums:   map[string][]string{"f3test_test.state": {"new", "gone"}},
+	}
+
+	drifts := conformance.Check("Thing", thing{})
+	expected := []string{
+		`Thing.count: type string is incompatible with integer`,
+		`Thing.extra: field is not defined by the swagger`,
+		`Thing.name: required property is not mapped`,
+		`Thing.state: enum value "done" is missing in f3test_test.state`,
+		`Thing.state: enum value "gone" of f3test_test.state is not defined by the swagger`,
+	}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("Expected the drifts %q, got %q", expected, drifts)
+	}
+
+	conformance.Ignore = []string{"*.count", "Thing.extra", "Thing.name", "Thing.st*"}
+	if drifts = conformance.Check("Thing", thing{}); len(drifts) > 0 {
+		t.Errorf("Expected the drifts to be ignored, got %q", drifts)
+	}
+	if drifts = conformance.Check("Missing", thing{}); len(drifts) != 1 {
+		t.Errorf("Expected a missing definition to be reported, got %q", drifts)
+	}
+}
+
+func TestConformance_CheckRequest(t *testing.T) {
+	conformance := &f3test.Conformance{
+		Swagger: loadTestSwagger(t),
+		Enums:   map[string][]string{"f3test_test.state": {"new", "done"}},
+		Ignore:  []string{"*.count", "*.name"},
+	}
+
+	drifts := conformance.CheckRequest("Thing", thing{})
+	expected := []string{
+		`Thing.done: required property is mapped to a field with omitempty, which drops the valid empty value`,
+		`Thing.extra: field is not defined by the swagger and sent, when empty`,
+		`Thing.tags: optional property is mapped to a field without omitempty`,
+	}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("Expected the drifts %q, got %q", expected, drifts)
+	}
+
+	type request struct {
+		Id    string   `json:"id,omitempty"`
+		Tags  []string `json:"tags,omitempty"`
+		Done  bool     `json:"done"`
+		Extra bool     `json:"extra,omitempty"`
+	}
+	expected = []string{`Thing.parent: property is not mapped`, `Thing.state: property is not mapped`}
+	if drifts = conformance.CheckRequest("Thing", request{}); !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("Expected the read only property to be optional, got %q", drifts)
+	}
+}
+
+func TestParseEnums(t *testing.T) {
+	enums, e := f3test.ParseEnums("../f3")
+	if e != nil {
+		t.Fatalf("Failed to parse the enums: %s", e)
+	}
+	expected := []string{"pending", "confirmed", "closed", "failed"}
+	if !reflect.DeepEqual(enums["f3.AccountStatusString"], expected) {
+		t.Errorf("Expected the account status values %q, got %q", expected, enums["f3.AccountStatusString"])
+	}
+}